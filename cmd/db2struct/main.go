@@ -1,20 +1,35 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/Shelnutt2/db2struct"
 	goopt "github.com/droundy/goopt"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/howeyc/gopass"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
-var mariadbHost = os.Getenv("MYSQL_HOST")
+var mariadbHost string
 var mariadbHostPassed = goopt.String([]string{"-H", "--host"}, "", "Host to check mariadb status of")
-var mariadbPort = goopt.Int([]string{"--mysql_port"}, 3306, "Specify a port to connect to")
+var mariadbPort = goopt.Int([]string{"--mysql_port", "--port"}, 0, "Specify a port to connect to (default 3306, 5432 for postgres)")
+var defaultsFile = goopt.String([]string{"--defaults-file"}, "", "Read the [client] connection options of a mysql option file (default ~/.my.cnf)")
+var driver = goopt.String([]string{"--driver"}, "mysql", "Database driver to use: "+strings.Join(db2struct.Introspectors(), ", "))
+var dsn = goopt.String([]string{"--dsn"}, "", "Data source name to connect with, such as a go-sql-driver/mysql DSN or the database file for sqlite")
+var migrationsDir = goopt.String([]string{"--migrations"}, "", "Read the table from replaying the up migrations of a mysql migrations directory instead of a database")
+var ddlFile = goopt.String([]string{"--ddl"}, "", "Read the table from the CREATE TABLE statements of a mysql DDL file instead of a database, - reads from stdin")
 var mariadbTable = goopt.String([]string{"-t", "--table"}, "", "Table to build struct from")
+var allTables = goopt.Flag([]string{"--all-tables"}, []string{}, "Build a struct for every table of the database", "")
+var includeTables = goopt.String([]string{"--tables"}, "", "Comma separated glob or /regexp/ patterns of the tables to build structs from with --all-tables")
+var excludeTables = goopt.String([]string{"--exclude-tables"}, "", "Comma separated glob or /regexp/ patterns of the tables to skip with --all-tables")
+var outDir = goopt.String([]string{"--out-dir"}, "", "Save one file per table in this directory with --all-tables")
 var mariadbDatabase = goopt.String([]string{"-d", "--database"}, "nil", "Database to for connection")
 var mariadbPassword *string
 var mariadbUser = goopt.String([]string{"-u", "--user"}, "user", "user to connect to database")
@@ -24,8 +39,21 @@ var structName = goopt.String([]string{"--struct"}, "", "name to set for struct"
 
 var jsonAnnotation = goopt.Flag([]string{"--json"}, []string{"--no-json"}, "Add json annotations (default)", "Disable json annotations")
 var gormAnnotation = goopt.Flag([]string{"--gorm"}, []string{}, "Add gorm annotations (tags)", "")
-var gureguTypes = goopt.Flag([]string{"--guregu"}, []string{}, "Add guregu null types", "")
+var tags = goopt.String([]string{"--tags"}, "", "Comma separated tags to add, such as db,json,yaml, of "+strings.Join(db2struct.TagEmitters(), ", "))
+var primaryKeyMethod = goopt.Flag([]string{"--primary-key"}, []string{}, "Add a PrimaryKey method, returning a key struct for composite primary keys", "")
+var constructor = goopt.Flag([]string{"--constructor"}, []string{}, "Add a New function setting the literal column defaults", "")
+var relations = goopt.Flag([]string{"--relations"}, []string{}, "Add association fields for foreign keys with gorm and bun relation tags", "")
+var manyToMany = goopt.Flag([]string{"--many2many"}, []string{}, "Generate many2many fields instead of structs for join tables with --all-tables", "")
+var omitEmpty = goopt.Flag([]string{"--omitempty"}, []string{}, "Add omitempty to the encoding tags, such as json and yaml, of nullable columns", "")
+var gureguTypes = goopt.Flag([]string{"--guregu"}, []string{}, "Add guregu null types, same as --nullable=guregu", "")
+var nullTypes = goopt.String([]string{"--nullable"}, "", "Types of nullable columns: sql, guregu, pointer or generic (default sql)")
+var genericNullType = goopt.String([]string{"--generic-type"}, "", "Generic type of nullable columns with --nullable=generic, such as github.com/samber/mo.Option (default sql.Null)")
+var typeMapFile = goopt.String([]string{"--type-map"}, "", "JSON file mapping data types, column types or table.column names to go types")
+var typeMappings = goopt.Strings([]string{"--type"}, "key=type", "Map a data type, column type or table.column name to a go type, such as decimal=github.com/shopspring/decimal.Decimal")
+var jsonSamples = goopt.Int([]string{"--json-samples"}, 0, "Infer structs for json columns from this many non-null values of each column (default off)")
+var fallbackType = goopt.String([]string{"--fallback-type"}, "", "Go type to use for columns of unknown data types, such as []byte (default fail)")
 var targetFile = goopt.String([]string{"--target"}, "", "Save file path")
+var headerComment = goopt.String([]string{"--header"}, "", "Comment to add above the package clause, such as \"Code generated by db2struct. DO NOT EDIT.\"")
 
 func init() {
 	goopt.OptArg([]string{"-p", "--password"}, "", "Mysql password", getMariadbPassword)
@@ -36,7 +64,11 @@ func init() {
 		return "Mariadb http Check"
 	}
 	goopt.Version = "0.0.2"
-	goopt.Summary = "db2struct [-H] [-p] [-v] --package pkgName --struct structName --database databaseName --table tableName"
+	goopt.Summary = "db2struct [-H] [-p] [-v] --package pkgName --struct structName --database databaseName --table tableName\n" +
+		"       db2struct --driver sqlite --dsn file.db --package pkgName --struct structName --table tableName\n" +
+		"       db2struct --ddl schema.sql --package pkgName --struct structName --table tableName\n" +
+		"       db2struct --migrations migrationsDir --package pkgName --struct structName --table tableName\n" +
+		"       db2struct [-H] [-p] [-v] --package pkgName --database databaseName --all-tables [--tables patterns] [--out-dir dir]"
 
 	//Parse options
 	goopt.Parse(nil)
@@ -45,73 +77,223 @@ func init() {
 
 func main() {
 
-	// Username is required
-	if mariadbUser == nil || *mariadbUser == "user" {
-		fmt.Println("Username is required! Add it with --user=name")
+	// If packageName is not set we need to default it
+	if packageName == nil || *packageName == "" {
+		*packageName = "newpackage"
+	}
+
+	if *allTables {
+		generateAllTables()
 		return
 	}
 
-	// If a mariadb host is passed use it
-	if mariadbHostPassed != nil && *mariadbHostPassed != "" {
-		mariadbHost = *mariadbHostPassed
+	if mariadbTable == nil || *mariadbTable == "" {
+		fmt.Println("Table can not be null")
+		return
+	}
+	if *manyToMany {
+		fmt.Println("--many2many is only supported with --all-tables")
+		return
 	}
 
-	if mariadbPassword != nil && *mariadbPassword == "" {
-		fmt.Print("Password: ")
-		pass, err := gopass.GetPasswd()
-		stringPass := string(pass)
-		mariadbPassword = &stringPass
-		if err != nil {
-			fmt.Println("Error reading password: " + err.Error())
-			return
+	var table *db2struct.Table
+	var err error
+	if *jsonSamples > 0 && ((ddlFile != nil && *ddlFile != "") || (migrationsDir != nil && *migrationsDir != "")) {
+		fmt.Println("--json-samples is only supported for databases")
+		return
+	}
+	if migrationsDir != nil && *migrationsDir != "" {
+		if *verbose {
+			fmt.Println("Replaying migrations in " + *migrationsDir)
 		}
-	} else if mariadbPassword == nil {
-		p := ""
-		mariadbPassword = &p
+		table, err = db2struct.DescribeMigrations(*migrationsDir, *mariadbTable)
+	} else if ddlFile != nil && *ddlFile != "" {
+		table, err = describeDDLTable()
+	} else {
+		table, err = describeTable()
 	}
 
-	if *verbose {
-		fmt.Println("Connecting to mysql server " + mariadbHost + ":" + strconv.Itoa(*mariadbPort))
+	if err != nil {
+		fmt.Println("Error in selecting column data information: " + err.Error())
+		return
 	}
 
-	if mariadbDatabase == nil || *mariadbDatabase == "" {
-		fmt.Println("Database can not be null")
+	// If structName is not set we need to default it
+	if structName == nil || *structName == "" {
+		*structName = "newstruct"
+	}
+	// Generate struct string based on the table columns
+	options, err := generateOptions()
+	if err != nil {
+		fmt.Println(err.Error())
 		return
 	}
+	struc, err := db2struct.GenerateStruct(table, *structName, options)
 
-	if mariadbTable == nil || *mariadbTable == "" {
-		fmt.Println("Table can not be null")
+	if err != nil {
+		fmt.Println("Error in creating struct from json: " + err.Error())
 		return
 	}
+	writeStruct(struc)
+}
 
-	columnDataTypes, columnsSorted, err := db2struct.GetColumnsFromMysqlTable(*mariadbUser, *mariadbPassword, mariadbHost, *mariadbPort, *mariadbDatabase, *mariadbTable)
+// generateAllTables builds a struct for every table of the database matching the table filters
+func generateAllTables() {
+	if (ddlFile != nil && *ddlFile != "") || (migrationsDir != nil && *migrationsDir != "") {
+		fmt.Println("--all-tables is only supported for databases")
+		return
+	}
+	introspector, err := openIntrospector()
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	defer introspector.Close()
 
+	options, err := generateOptions()
 	if err != nil {
-		fmt.Println("Error in selecting column data information from mysql information schema")
+		fmt.Println(err.Error())
 		return
 	}
 
-	// If structName is not set we need to default it
-	if structName == nil || *structName == "" {
-		*structName = "newstruct"
+	described, err := introspector.DescribeSchema()
+	if err != nil {
+		fmt.Println("Error in selecting column data information: " + err.Error())
+		return
 	}
-	// If packageName is not set we need to default it
-	if packageName == nil || *packageName == "" {
-		*packageName = "newpackage"
+
+	names := make([]string, 0, len(described))
+	describedByName := make(map[string]*db2struct.Table)
+	for _, table := range described {
+		names = append(names, table.Name)
+		describedByName[table.Name] = table
+	}
+	names, err = db2struct.FilterTables(names, splitPatterns(*includeTables), splitPatterns(*excludeTables))
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	tables := make([]*db2struct.Table, 0, len(names))
+	for _, name := range names {
+		if err = sampleJSONColumns(introspector, describedByName[name]); err != nil {
+			fmt.Println("Error in sampling json columns: " + err.Error())
+			return
+		}
+		tables = append(tables, describedByName[name])
+	}
+	if *manyToMany {
+		options.JoinTables = db2struct.JoinTables(tables)
+	}
+
+	if outDir != nil && *outDir != "" {
+		if err = os.MkdirAll(*outDir, 0755); err != nil {
+			fmt.Println("Create directory fail: " + err.Error())
+			return
+		}
+		for _, table := range tables {
+			if options.IsJoinTable(table) {
+				continue
+			}
+			struc, err := db2struct.GenerateStruct(table, "", options)
+			if err != nil {
+				fmt.Println("Error in creating struct for table " + table.Name + ": " + err.Error())
+				return
+			}
+			path := filepath.Join(*outDir, table.Name+".go")
+			if err = os.WriteFile(path, struc, 0644); err != nil {
+				fmt.Println("Save File fail: " + err.Error())
+				return
+			}
+			if *verbose {
+				fmt.Printf("wrote %d bytes to %s\n", len(struc), path)
+			}
+		}
+		return
 	}
-	// Generate struct string based on columnDataTypes
-	struc, err := db2struct.Generate(*columnDataTypes, columnsSorted, *mariadbTable, *structName, *packageName, *jsonAnnotation, *gormAnnotation, *gureguTypes)
 
+	struc, err := db2struct.GenerateStructs(tables, options)
 	if err != nil {
-		fmt.Println("Error in creating struct from json: " + err.Error())
+		fmt.Println("Error in creating structs: " + err.Error())
 		return
 	}
+	writeStruct(struc)
+}
+
+// generateOptions returns the options of the generated structs
+func generateOptions() (db2struct.GenerateOptions, error) {
+	options := db2struct.GenerateOptions{
+		PackageName:      *packageName,
+		NullTypes:        db2struct.NullTypesSQL,
+		HeaderComment:    *headerComment,
+		TableNameMethod:  *gormAnnotation,
+		PrimaryKeyMethod: *primaryKeyMethod,
+		Constructor:      *constructor,
+		Relations:        *relations,
+		FallbackType:     *fallbackType,
+	}
+	if *gormAnnotation {
+		options.Tags = append(options.Tags, db2struct.TagGorm)
+	}
+	if *jsonAnnotation {
+		options.Tags = append(options.Tags, db2struct.TagJSON)
+	}
+	for _, tag := range strings.Split(*tags, ",") {
+		if tag = strings.TrimSpace(tag); tag == "" || (tag == db2struct.TagJSON && *jsonAnnotation) ||
+			(tag == db2struct.TagGorm && *gormAnnotation) {
+			continue
+		}
+		options.Tags = append(options.Tags, tag)
+	}
+	options.OmitEmpty = *omitEmpty
+	if *gureguTypes {
+		options.NullTypes = db2struct.NullTypesGuregu
+	}
+	if *nullTypes != "" {
+		if *gureguTypes && *nullTypes != db2struct.NullTypesGuregu {
+			return options, fmt.Errorf("--guregu conflicts with --nullable=%s", *nullTypes)
+		}
+		options.NullTypes = *nullTypes
+	}
+	options.GenericNullType = *genericNullType
+
+	options.TypeMap = db2struct.TypeMap{}
+	if typeMapFile != nil && *typeMapFile != "" {
+		typeMap, err := db2struct.LoadTypeMap(*typeMapFile)
+		if err != nil {
+			return options, err
+		}
+		options.TypeMap = typeMap
+	}
+	for _, typeMapping := range *typeMappings {
+		key, mapping, err := db2struct.ParseTypeMapping(typeMapping)
+		if err != nil {
+			return options, err
+		}
+		options.TypeMap[key] = mapping
+	}
+	return options, nil
+}
+
+// splitPatterns splits a comma separated list of table patterns
+func splitPatterns(patterns string) []string {
+	var split []string
+	for _, pattern := range strings.Split(patterns, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			split = append(split, pattern)
+		}
+	}
+	return split
+}
+
+// writeStruct appends the generated source to the target file, or prints it if there is none
+func writeStruct(struc []byte) {
 	if targetFile != nil && *targetFile != "" {
 		file, err := os.OpenFile(*targetFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
 			fmt.Println("Open File fail: " + err.Error())
 			return
 		}
+		defer file.Close()
 		length, err := file.WriteString(string(struc))
 		if err != nil {
 			fmt.Println("Save File fail: " + err.Error())
@@ -123,6 +305,149 @@ func main() {
 	}
 }
 
+// describeDDLTable parses the columns of the table from the DDL file
+func describeDDLTable() (*db2struct.Table, error) {
+	if *ddlFile == "-" {
+		return db2struct.DescribeMysqlDDL(os.Stdin, *mariadbTable)
+	}
+
+	if *verbose {
+		fmt.Println("Reading DDL file " + *ddlFile)
+	}
+	file, err := os.Open(*ddlFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return db2struct.DescribeMysqlDDL(file, *mariadbTable)
+}
+
+// describeTable connects to the database with the introspector of the driver and selects the columns of the table
+func describeTable() (*db2struct.Table, error) {
+	introspector, err := openIntrospector()
+	if err != nil {
+		return nil, err
+	}
+	defer introspector.Close()
+	table, err := introspector.DescribeTable(*mariadbTable)
+	if err != nil {
+		return nil, err
+	}
+	return table, sampleJSONColumns(introspector, table)
+}
+
+// sampleJSONColumns reads the samples of the json columns of a table when --json-samples is set
+func sampleJSONColumns(introspector db2struct.Introspector, table *db2struct.Table) error {
+	if *jsonSamples <= 0 {
+		return nil
+	}
+	sampler, ok := introspector.(db2struct.SamplingIntrospector)
+	if !ok {
+		return fmt.Errorf("the %s driver can not sample json columns", *driver)
+	}
+	return sampler.SampleJSONColumnsContext(context.Background(), table, *jsonSamples)
+}
+
+// openIntrospector connects to the database with the introspector of the driver, drivers connecting to a server
+// are used when no DSN is given
+func openIntrospector() (db2struct.Introspector, error) {
+	if dsn != nil && *dsn != "" {
+		if *verbose {
+			fmt.Println("Connecting to " + *driver + " database with the DSN")
+		}
+		return db2struct.NewIntrospector(*driver, db2struct.ConnectionConfig{DSN: *dsn})
+	}
+	if *driver == "sqlite" {
+		return nil, errors.New("DSN is required for sqlite! Add it with --dsn=file.db")
+	}
+
+	if err := prepareServerConnection(); err != nil {
+		return nil, err
+	}
+	return db2struct.NewIntrospector(*driver, db2struct.ConnectionConfig{
+		User:     *mariadbUser,
+		Password: *mariadbPassword,
+		Host:     mariadbHost,
+		Port:     *mariadbPort,
+		Database: *mariadbDatabase,
+	})
+}
+
+// prepareServerConnection checks the connection options and reads the password if requested
+//
+// Options which are not passed are read from the mysql defaults file and environment for mysql.
+func prepareServerConnection() error {
+
+	if *driver == "mysql" {
+		if err := applyMysqlClientConfig(); err != nil {
+			return err
+		}
+	}
+
+	// Username is required
+	if mariadbUser == nil || *mariadbUser == "user" {
+		return errors.New("Username is required! Add it with --user=name")
+	}
+
+	// If a mariadb host is passed use it
+	if mariadbHostPassed != nil && *mariadbHostPassed != "" {
+		mariadbHost = *mariadbHostPassed
+	}
+
+	if mariadbPassword != nil && *mariadbPassword == "" {
+		fmt.Print("Password: ")
+		pass, err := gopass.GetPasswd()
+		stringPass := string(pass)
+		mariadbPassword = &stringPass
+		if err != nil {
+			return errors.New("Error reading password: " + err.Error())
+		}
+	} else if mariadbPassword == nil {
+		p := ""
+		mariadbPassword = &p
+	}
+
+	if *mariadbPort == 0 {
+		*mariadbPort = 3306
+		if *driver == "postgres" {
+			*mariadbPort = 5432
+		}
+	}
+
+	if *verbose {
+		fmt.Println("Connecting to " + *driver + " server " + mariadbHost + ":" + strconv.Itoa(*mariadbPort))
+	}
+
+	if mariadbDatabase == nil || *mariadbDatabase == "" {
+		return errors.New("Database can not be null")
+	}
+	return nil
+}
+
+// applyMysqlClientConfig uses the options of the mysql defaults file and environment which are not passed
+func applyMysqlClientConfig() error {
+	config, err := db2struct.MysqlClientConfig(*defaultsFile)
+	if err != nil {
+		return errors.New("Error reading mysql options: " + err.Error())
+	}
+	if *mariadbUser == "user" && config.User != "" {
+		*mariadbUser = config.User
+	}
+	if mariadbHostPassed == nil || *mariadbHostPassed == "" {
+		mariadbHost = config.Host
+	}
+	if *mariadbPort == 0 {
+		*mariadbPort = config.Port
+	}
+	if mariadbPassword == nil && config.Password != "" {
+		mariadbPassword = &config.Password
+	}
+	if *mariadbDatabase == "nil" && config.Database != "" {
+		*mariadbDatabase = config.Database
+	}
+	return nil
+}
+
 func getMariadbPassword(password string) error {
 	mariadbPassword = new(string)
 	*mariadbPassword = password
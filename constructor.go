@@ -0,0 +1,139 @@
+package db2struct
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// numericLiteral matches integer and decimal literals, such as -1, 0.00 or 1e3
+var numericLiteral = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// bitOrHexLiteral matches mysql bit and hex literals, such as b'0' or X'0F', which are not strings
+var bitOrHexLiteral = regexp.MustCompile(`^[bBxX]'[0-9a-fA-F]*'$`)
+
+// constructor is the New function of a struct, which returns a struct with the literal defaults of the table
+type constructor struct {
+	table      *Table
+	structName string
+	// fields and values are the field names and go values of the columns with literal defaults in column order
+	fields []string
+	values []string
+}
+
+// tableConstructor returns the New function of the struct of a table. Columns whose default is an expression, such
+// as CURRENT_TIMESTAMP or uuid(), or whose go type can not hold a constant, such as time.Time, are left to the
+// database.
+func tableConstructor(table *Table, structName string, fieldTypes map[string]string, decls []typeDeclaration, options *GenerateOptions) *constructor {
+	c := &constructor{table: table, structName: structName}
+	for _, column := range table.Columns {
+		literal, ok := column.defaultLiteral(table.dialect())
+		if !ok {
+			continue
+		}
+		// zero values are left out, as they are already set
+		if value, ok := goValue(literal, fieldTypes[column.Name], decls); ok && value != "0" && value != "false" && value != `""` {
+			c.fields = append(c.fields, options.fieldName(column.Name))
+			c.values = append(c.values, value)
+		}
+	}
+	return c
+}
+
+// name returns the name of the New function, such as NewUsers
+func (c *constructor) name() string {
+	return "New" + strings.ToUpper(c.structName[:1]) + c.structName[1:]
+}
+
+// generate returns the New function
+func (c *constructor) generate(imports map[string]bool) string {
+	src := fmt.Sprintf("// %s returns a new %s with the column defaults of the %s table\nfunc %s() *%s {\nreturn &%s{",
+		c.name(), c.structName, c.table.Name, c.name(), c.structName, c.structName)
+	if len(c.fields) > 0 {
+		src += "\n"
+	}
+	for i, field := range c.fields {
+		src += fmt.Sprintf("%s: %s,\n", field, c.values[i])
+	}
+	return src + "}\n}"
+}
+
+// defaultLiteral returns the value of the default of the column if it is a literal, such as new for 'new' or
+// 'new'::character varying. Mysql string defaults are only quoted by MariaDB, so unquoted mysql defaults are literals
+// unless they are a function call or a keyword such as CURRENT_TIMESTAMP.
+func (c *Column) defaultLiteral(dialect string) (string, bool) {
+	if c.Default == nil || c.autoIncrement() || c.generated() || strings.Contains(strings.ToLower(c.Extra), "default_generated") {
+		return "", false
+	}
+	value := strings.TrimSpace(*c.Default)
+	if dialect == DialectPostgres {
+		// strip the casts of postgres defaults, such as ::character varying
+		for i := strings.LastIndex(value, "::"); i > strings.LastIndex(value, "'"); i = strings.LastIndex(value, "::") {
+			value = value[:i]
+		}
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1), true
+	}
+	if numericLiteral.MatchString(value) || strings.EqualFold(value, "true") || strings.EqualFold(value, "false") {
+		return value, true
+	}
+	if dialect != DialectMysql || strings.Contains(value, "(") || bitOrHexLiteral.MatchString(value) {
+		return "", false
+	}
+	switch strings.ToUpper(value) {
+	case "NULL", "CURRENT_TIMESTAMP", "CURRENT_DATE", "CURRENT_TIME", "LOCALTIME", "LOCALTIMESTAMP":
+		return "", false
+	}
+	return value, true
+}
+
+// goValue returns the go expression of a literal value of the given go type, such as
+// sql.NullString{String: "new", Valid: true}, false if the type can not hold the literal. The values of enum types
+// are their constants.
+func goValue(literal string, goType string, decls []typeDeclaration) (string, bool) {
+	switch goType {
+	case "string":
+		return strconv.Quote(literal), true
+	case golangBool:
+		value, err := strconv.ParseBool(literal)
+		return strconv.FormatBool(value), err == nil
+	case golangInt, golangInt8, golangInt16, golangInt32, golangInt64:
+		value, err := strconv.ParseInt(literal, 10, 64)
+		return strconv.FormatInt(value, 10), err == nil
+	case golangUint8, golangUint16, golangUint32, golangUint64:
+		value, err := strconv.ParseUint(literal, 10, 64)
+		return strconv.FormatUint(value, 10), err == nil
+	case golangFloat32, golangFloat64:
+		value, err := strconv.ParseFloat(literal, 64)
+		return strconv.FormatFloat(value, 'g', -1, 64), err == nil
+	}
+
+	wrappers := map[string]struct{ format, valueType string }{
+		sqlNullString:    {"sql.NullString{String: %s, Valid: true}", "string"},
+		sqlNullInt:       {"sql.NullInt64{Int64: %s, Valid: true}", golangInt64},
+		sqlNullFloat:     {"sql.NullFloat64{Float64: %s, Valid: true}", golangFloat64},
+		sqlNullBool:      {"sql.NullBool{Bool: %s, Valid: true}", golangBool},
+		sqlNullTime:      {"sql.NullTime{Time: %s, Valid: true}", golangTime},
+		gureguNullString: {"null.StringFrom(%s)", "string"},
+		gureguNullInt:    {"null.IntFrom(%s)", golangInt64},
+		gureguNullFloat:  {"null.FloatFrom(%s)", golangFloat64},
+		gureguNullBool:   {"null.BoolFrom(%s)", golangBool},
+	}
+	if wrapper, ok := wrappers[goType]; ok {
+		value, ok := goValue(literal, wrapper.valueType, decls)
+		return fmt.Sprintf(wrapper.format, value), ok
+	}
+
+	for _, decl := range decls {
+		if enum, ok := decl.(enumType); ok && enum.name == goType && !enum.set {
+			for i, value := range enum.values {
+				if value == literal {
+					return enum.constantNames()[i], true
+				}
+			}
+		}
+	}
+	return "", false
+}
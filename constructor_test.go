@@ -0,0 +1,101 @@
+package db2struct
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestColumnDefaultLiteral(t *testing.T) {
+	literal := func(dialect string, value string, extra string) interface{} {
+		column := &Column{Default: &value, Extra: extra}
+		if literal, ok := column.defaultLiteral(dialect); ok {
+			return literal
+		}
+		return nil
+	}
+	Convey("Should unquote string defaults and strip postgres casts", t, func() {
+		So(literal(DialectMysql, "new", ""), ShouldEqual, "new")
+		So(literal(DialectMysql, "'it''s'", ""), ShouldEqual, "it's")
+		So(literal(DialectPostgres, "'new'::character varying", ""), ShouldEqual, "new")
+		So(literal(DialectPostgres, "'-1'::integer", ""), ShouldEqual, "-1")
+		So(literal(DialectSqlite, "'a::b'", ""), ShouldEqual, "a::b")
+	})
+	Convey("Should return numeric and boolean defaults", t, func() {
+		So(literal(DialectMysql, "-1.50", ""), ShouldEqual, "-1.50")
+		So(literal(DialectPostgres, "true", ""), ShouldEqual, "true")
+		So(literal(DialectSqlite, "0", ""), ShouldEqual, "0")
+	})
+	Convey("Should not return expression defaults", t, func() {
+		So(literal(DialectMysql, "CURRENT_TIMESTAMP", "DEFAULT_GENERATED"), ShouldBeNil)
+		So(literal(DialectMysql, "current_timestamp()", ""), ShouldBeNil)
+		So(literal(DialectMysql, "CURRENT_TIMESTAMP", ""), ShouldBeNil)
+		So(literal(DialectMysql, "(uuid())", ""), ShouldBeNil)
+		So(literal(DialectMysql, "b'0'", ""), ShouldBeNil)
+		So(literal(DialectMysql, "X'0F'", ""), ShouldBeNil)
+		So(literal(DialectMysql, "0", "auto_increment"), ShouldBeNil)
+		So(literal(DialectPostgres, "nextval('users_id_seq'::regclass)", ""), ShouldBeNil)
+		So(literal(DialectPostgres, "now()", ""), ShouldBeNil)
+		So(literal(DialectSqlite, "CURRENT_TIMESTAMP", ""), ShouldBeNil)
+	})
+}
+
+func TestConstructorGenerate(t *testing.T) {
+	expectedStruct :=
+		`package test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+type Order struct {
+	ID        int64
+	Status    OrderStatus
+	Quantity  int32
+	Discount  float64
+	Gift      bool
+	Note      sql.NullString
+	Reference string
+	CreatedAt time.Time
+}
+
+// NewOrder returns a new Order with the column defaults of the orders table
+func NewOrder() *Order {
+	return &Order{
+		Status:   OrderStatusPending,
+		Quantity: 1,
+		Discount: 0.5,
+		Gift:     true,
+		Note:     sql.NullString{String: "it's a gift", Valid: true},
+	}
+}
+`
+	value := func(value string) *string {
+		return &value
+	}
+	table := &Table{Name: "orders", Columns: []*Column{
+		{Name: "id", DataType: "bigint", ColumnType: "bigint", Key: KeyPrimary, Extra: "auto_increment", Default: value("0")},
+		{Name: "status", DataType: "enum", ColumnType: "enum('pending','paid')", Default: value("pending")},
+		{Name: "quantity", DataType: "int", ColumnType: "int", Default: value("1")},
+		{Name: "discount", DataType: "double", ColumnType: "double", Default: value("0.50")},
+		{Name: "gift", DataType: "bool", ColumnType: "tinyint(1)", Default: value("1")},
+		{Name: "note", DataType: "varchar", ColumnType: "varchar(255)", Nullable: true, Default: value("it's a gift")},
+		{Name: "reference", DataType: "char", ColumnType: "char(36)", Default: value("(uuid())")},
+		{Name: "created_at", DataType: "datetime", ColumnType: "datetime", Default: value("CURRENT_TIMESTAMP"), Extra: "DEFAULT_GENERATED"},
+	}}
+	bytes, err := GenerateStruct(table, "Order", GenerateOptions{PackageName: "test", Constructor: true})
+	Convey("Should set the literal defaults of the columns in the New function", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldStartWith, expectedStruct)
+	})
+
+	table = &Table{Name: "events", Columns: []*Column{{Name: "id", DataType: "int", Key: KeyPrimary}}}
+	bytes, err = GenerateStruct(table, "event", GenerateOptions{PackageName: "test", Constructor: true})
+	Convey("Should generate an exported New function without defaults", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldContainSubstring, "func NewEvent() *event {\n\treturn &event{}\n}")
+	})
+}
@@ -0,0 +1,152 @@
+package db2struct
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// enumType is a named type generated for an enum or set column. Enum columns get a string type with a constant for
+// each value, set columns get a slice of a string type with a constant for each value.
+type enumType struct {
+	name   string
+	table  string
+	column string
+	values []string
+	set    bool
+}
+
+// columnEnumType returns the type of an enum or set column with the given name
+func columnEnumType(table *Table, column *Column, name string) (enumType, bool) {
+	if values := column.EnumValues(); values != nil {
+		return enumType{name: name, table: table.Name, column: column.Name, values: values}, true
+	}
+	if values := column.SetValues(); values != nil {
+		return enumType{name: name, table: table.Name, column: column.Name, values: values, set: true}, true
+	}
+	return enumType{}, false
+}
+
+// valueName returns the name of the string type of the values
+func (e enumType) valueName() string {
+	if e.set {
+		return e.name + "Value"
+	}
+	return e.name
+}
+
+// constantNames returns the names of the constants of the values, prefixed with the type name
+func (e enumType) constantNames() []string {
+	names := make([]string, 0, len(e.values))
+	seen := map[string]bool{}
+	for i, value := range e.values {
+		name := e.name + identifierName(value)
+		if seen[name] {
+			name += strconv.Itoa(i)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// identifierName returns an exported identifier for a value, such as InProgress for in-progress
+func identifierName(value string) string {
+	name := strings.Trim(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, value), "_")
+	if name == "" {
+		name = "empty"
+	}
+	return fmtFieldName(stringifyFirstChar(name))
+}
+
+// generate returns the unformatted declaration of the type, the constants of its values and its methods, and adds
+// the imports they require
+func (e enumType) generate(imports map[string]bool) string {
+	imports["database/sql/driver"] = true
+	imports["fmt"] = true
+	if e.set {
+		imports["strings"] = true
+		return e.generateValues("// %s is a value of the %s set column of the %s table\n") + "\n\n" + e.generateSet()
+	}
+	return e.generateValues("// %s is a value of the %s enum column of the %s table\n") + "\n\n" + e.generateEnum()
+}
+
+// generateValues returns the declaration of the string type of the values, its constants and its IsValid and String
+// methods
+func (e enumType) generateValues(comment string) string {
+	name := e.valueName()
+	names := e.constantNames()
+	receiver := strings.ToLower(name[:1])
+
+	src := fmt.Sprintf(comment+"type %s string\n\n", name, e.column, e.table, name)
+	src += fmt.Sprintf("// Values of %s\nconst (\n", name)
+	for i, value := range e.values {
+		src += fmt.Sprintf("%s %s = %s\n", names[i], name, strconv.Quote(value))
+	}
+	src += ")\n\n"
+
+	src += fmt.Sprintf("// IsValid reports whether %s is one of the values of the %s column\n", receiver, e.column)
+	src += fmt.Sprintf("func (%s %s) IsValid() bool {\nswitch %s {\ncase %s:\nreturn true\n}\nreturn false\n}\n\n",
+		receiver, name, receiver, strings.Join(names, ", "))
+
+	src += fmt.Sprintf("// String returns the value of %s\n", receiver)
+	src += fmt.Sprintf("func (%s %s) String() string {\nreturn string(%s)\n}", receiver, name, receiver)
+	return src
+}
+
+// generateEnum returns the Scan and Value methods of an enum type
+func (e enumType) generateEnum() string {
+	receiver := strings.ToLower(e.name[:1])
+
+	src := "// Scan implements the sql.Scanner interface\n"
+	src += fmt.Sprintf("func (%s *%s) Scan(value interface{}) error {\n", receiver, e.name)
+	src += "switch v := value.(type) {\ncase string:\n"
+	src += fmt.Sprintf("*%s = %s(v)\ncase []byte:\n*%s = %s(v)\n", receiver, e.name, receiver, e.name)
+	src += fmt.Sprintf("default:\nreturn fmt.Errorf(\"cannot scan %%T into %s\", value)\n}\n", e.name)
+	src += fmt.Sprintf("if !%s.IsValid() {\nreturn fmt.Errorf(\"invalid %s %%q\", string(*%s))\n}\nreturn nil\n}\n\n", receiver, e.name, receiver)
+
+	src += "// Value implements the driver.Valuer interface\n"
+	src += fmt.Sprintf("func (%s %s) Value() (driver.Value, error) {\n", receiver, e.name)
+	src += fmt.Sprintf("if !%s.IsValid() {\nreturn nil, fmt.Errorf(\"invalid %s %%q\", string(%s))\n}\n", receiver, e.name, receiver)
+	src += fmt.Sprintf("return string(%s), nil\n}", receiver)
+	return src
+}
+
+// generateSet returns the declaration of the slice type of a set column and its Contains, String, Scan and Value
+// methods, which read and write the comma separated values mysql uses
+func (e enumType) generateSet() string {
+	receiver := strings.ToLower(e.name[:1])
+	value := e.valueName()
+
+	src := fmt.Sprintf("// %s is the value of the %s set column of the %s table\ntype %s []%s\n\n", e.name, e.column, e.table, e.name, value)
+
+	src += fmt.Sprintf("// Contains reports whether %s contains the value\n", receiver)
+	src += fmt.Sprintf("func (%s %s) Contains(value %s) bool {\n", receiver, e.name, value)
+	src += fmt.Sprintf("for _, v := range %s {\nif v == value {\nreturn true\n}\n}\nreturn false\n}\n\n", receiver)
+
+	src += fmt.Sprintf("// String returns the comma separated values of %s\n", receiver)
+	src += fmt.Sprintf("func (%s %s) String() string {\n", receiver, e.name)
+	src += fmt.Sprintf("values := make([]string, 0, len(%s))\nfor _, v := range %s {\nvalues = append(values, string(v))\n}\n", receiver, receiver)
+	src += "return strings.Join(values, \",\")\n}\n\n"
+
+	src += "// Scan implements the sql.Scanner interface\n"
+	src += fmt.Sprintf("func (%s *%s) Scan(value interface{}) error {\n", receiver, e.name)
+	src += "var values string\nswitch v := value.(type) {\ncase string:\nvalues = v\ncase []byte:\nvalues = string(v)\n"
+	src += fmt.Sprintf("default:\nreturn fmt.Errorf(\"cannot scan %%T into %s\", value)\n}\n", e.name)
+	src += fmt.Sprintf("*%s = %s{}\nif values == \"\" {\nreturn nil\n}\n", receiver, e.name)
+	src += fmt.Sprintf("for _, v := range strings.Split(values, \",\") {\nif !%s(v).IsValid() {\n", value)
+	src += fmt.Sprintf("return fmt.Errorf(\"invalid %s value %%q\", v)\n}\n*%s = append(*%s, %s(v))\n}\nreturn nil\n}\n\n", e.name, receiver, receiver, value)
+
+	src += "// Value implements the driver.Valuer interface\n"
+	src += fmt.Sprintf("func (%s %s) Value() (driver.Value, error) {\n", receiver, e.name)
+	src += fmt.Sprintf("for _, v := range %s {\nif !v.IsValid() {\n", receiver)
+	src += fmt.Sprintf("return nil, fmt.Errorf(\"invalid %s value %%q\", string(v))\n}\n}\n", e.name)
+	src += fmt.Sprintf("return %s.String(), nil\n}", receiver)
+	return src
+}
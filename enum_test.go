@@ -0,0 +1,244 @@
+package db2struct
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEnumConstantNames(t *testing.T) {
+	enum := enumType{name: "OrderStatus", values: []string{"new", "in-progress", "2fast", "", "NEW", "in progress"}}
+	Convey("Should name the constants of the values after the type", t, func() {
+		So(enum.constantNames(), ShouldResemble, []string{
+			"OrderStatusNew", "OrderStatusInProgress", "OrderStatusTwoFast", "OrderStatusEmpty", "OrderStatusNEW",
+			"OrderStatusInProgress5",
+		})
+	})
+}
+
+func TestEnumGenerate(t *testing.T) {
+	expectedStruct :=
+		`package test
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+type Orders struct {
+	ID     int32
+	Status OrdersStatus
+	Kind   *OrdersKind
+}
+
+// OrdersStatus is a value of the status enum column of the orders table
+type OrdersStatus string
+
+// Values of OrdersStatus
+const (
+	OrdersStatusNew        OrdersStatus = "new"
+	OrdersStatusInProgress OrdersStatus = "in-progress"
+)
+
+// IsValid reports whether o is one of the values of the status column
+func (o OrdersStatus) IsValid() bool {
+	switch o {
+	case OrdersStatusNew, OrdersStatusInProgress:
+		return true
+	}
+	return false
+}
+
+// String returns the value of o
+func (o OrdersStatus) String() string {
+	return string(o)
+}
+
+// Scan implements the sql.Scanner interface
+func (o *OrdersStatus) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		*o = OrdersStatus(v)
+	case []byte:
+		*o = OrdersStatus(v)
+	default:
+		return fmt.Errorf("cannot scan %T into OrdersStatus", value)
+	}
+	if !o.IsValid() {
+		return fmt.Errorf("invalid OrdersStatus %q", string(*o))
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (o OrdersStatus) Value() (driver.Value, error) {
+	if !o.IsValid() {
+		return nil, fmt.Errorf("invalid OrdersStatus %q", string(o))
+	}
+	return string(o), nil
+}
+
+// OrdersKind is a value of the kind enum column of the orders table
+type OrdersKind string
+
+// Values of OrdersKind
+const (
+	OrdersKindA OrdersKind = "a"
+)
+
+// IsValid reports whether o is one of the values of the kind column
+func (o OrdersKind) IsValid() bool {
+	switch o {
+	case OrdersKindA:
+		return true
+	}
+	return false
+}
+
+// String returns the value of o
+func (o OrdersKind) String() string {
+	return string(o)
+}
+
+// Scan implements the sql.Scanner interface
+func (o *OrdersKind) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		*o = OrdersKind(v)
+	case []byte:
+		*o = OrdersKind(v)
+	default:
+		return fmt.Errorf("cannot scan %T into OrdersKind", value)
+	}
+	if !o.IsValid() {
+		return fmt.Errorf("invalid OrdersKind %q", string(*o))
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (o OrdersKind) Value() (driver.Value, error) {
+	if !o.IsValid() {
+		return nil, fmt.Errorf("invalid OrdersKind %q", string(o))
+	}
+	return string(o), nil
+}
+`
+	table := &Table{Name: "orders", Columns: []*Column{
+		{Name: "id", DataType: "int", ColumnType: "int(11)", Key: KeyPrimary},
+		{Name: "status", DataType: "enum", ColumnType: "enum('new','in-progress')"},
+		{Name: "kind", DataType: "enum", ColumnType: "enum('a')", Nullable: true},
+	}}
+	bytes, err := GenerateStruct(table, "", GenerateOptions{PackageName: "test"})
+	Convey("Should be able to generate typed enums", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+
+	bytes, err = GenerateStruct(table, "", GenerateOptions{PackageName: "test", NullTypes: NullTypesGeneric})
+	Convey("Should wrap nullable enums with the generic null type", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldContainSubstring, "Kind   sql.Null[OrdersKind]\n")
+	})
+
+	bytes, err = GenerateStruct(table, "", GenerateOptions{PackageName: "test", TypeMap: TypeMap{"enum": {Type: "string"}}})
+	Convey("Should not generate enums for columns with a type mapping", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldNotContainSubstring, "type OrdersStatus")
+		So(string(bytes), ShouldContainSubstring, "Status string\n")
+	})
+}
+
+func TestSetGenerate(t *testing.T) {
+	expectedSet :=
+		`// OrdersFlagsValue is a value of the flags set column of the orders table
+type OrdersFlagsValue string
+
+// Values of OrdersFlagsValue
+const (
+	OrdersFlagsGift    OrdersFlagsValue = "gift"
+	OrdersFlagsExpress OrdersFlagsValue = "express"
+)
+
+// IsValid reports whether o is one of the values of the flags column
+func (o OrdersFlagsValue) IsValid() bool {
+	switch o {
+	case OrdersFlagsGift, OrdersFlagsExpress:
+		return true
+	}
+	return false
+}
+
+// String returns the value of o
+func (o OrdersFlagsValue) String() string {
+	return string(o)
+}
+
+// OrdersFlags is the value of the flags set column of the orders table
+type OrdersFlags []OrdersFlagsValue
+
+// Contains reports whether o contains the value
+func (o OrdersFlags) Contains(value OrdersFlagsValue) bool {
+	for _, v := range o {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the comma separated values of o
+func (o OrdersFlags) String() string {
+	values := make([]string, 0, len(o))
+	for _, v := range o {
+		values = append(values, string(v))
+	}
+	return strings.Join(values, ",")
+}
+
+// Scan implements the sql.Scanner interface
+func (o *OrdersFlags) Scan(value interface{}) error {
+	var values string
+	switch v := value.(type) {
+	case string:
+		values = v
+	case []byte:
+		values = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into OrdersFlags", value)
+	}
+	*o = OrdersFlags{}
+	if values == "" {
+		return nil
+	}
+	for _, v := range strings.Split(values, ",") {
+		if !OrdersFlagsValue(v).IsValid() {
+			return fmt.Errorf("invalid OrdersFlags value %q", v)
+		}
+		*o = append(*o, OrdersFlagsValue(v))
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (o OrdersFlags) Value() (driver.Value, error) {
+	for _, v := range o {
+		if !v.IsValid() {
+			return nil, fmt.Errorf("invalid OrdersFlags value %q", string(v))
+		}
+	}
+	return o.String(), nil
+}
+`
+	table := &Table{Name: "orders", Columns: []*Column{
+		{Name: "flags", DataType: "set", ColumnType: "set('gift','express')"},
+		{Name: "extras", DataType: "set", ColumnType: "set('a')", Nullable: true},
+	}}
+	bytes, err := GenerateStruct(table, "", GenerateOptions{PackageName: "test"})
+	Convey("Should be able to generate typed sets", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldContainSubstring, "import (\n\t\"database/sql/driver\"\n\t\"fmt\"\n\t\"strings\"\n)\n")
+		So(string(bytes), ShouldContainSubstring, "\tFlags  OrdersFlags\n\tExtras *OrdersExtras\n")
+		So(string(bytes), ShouldContainSubstring, expectedSet)
+	})
+}
@@ -0,0 +1,95 @@
+package db2struct
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Introspector describes the tables of a database
+type Introspector interface {
+	// ListTables returns the names of the tables of the database in name order
+	ListTables() ([]string, error)
+	// DescribeTable describes a table of the database, a table which does not exist is an error
+	DescribeTable(table string) (*Table, error)
+	// DescribeSchema describes every table of the database in name order
+	DescribeSchema() ([]*Table, error)
+	// Close releases the connection to the database
+	Close() error
+}
+
+// ContextIntrospector is an Introspector which can cancel its queries with a context
+type ContextIntrospector interface {
+	Introspector
+	// ListTablesContext returns the names of the tables of the database in name order
+	ListTablesContext(ctx context.Context) ([]string, error)
+	// DescribeTableContext describes a table of the database, a table which does not exist is an error
+	DescribeTableContext(ctx context.Context, table string) (*Table, error)
+	// DescribeSchemaContext describes every table of the database in name order
+	DescribeSchemaContext(ctx context.Context) ([]*Table, error)
+}
+
+// Queryer runs the introspection queries, it is implemented by *sql.DB, *sql.Conn and *sql.Tx
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// ConnectionConfig holds the options to connect to a database with
+type ConnectionConfig struct {
+	User     string
+	Password string
+	// Host of the server, a host of the form unix:/path connects through a unix socket
+	Host     string
+	Port     int
+	Database string
+	// DSN is the data source name of drivers which do not connect to a server, such as the database file for sqlite
+	DSN string
+}
+
+// IntrospectorFactory connects to a database and returns its Introspector
+type IntrospectorFactory func(config ConnectionConfig) (Introspector, error)
+
+var (
+	introspectorsMu sync.RWMutex
+	introspectors   = make(map[string]IntrospectorFactory)
+)
+
+// RegisterIntrospector makes an Introspector available by the given driver name. The mysql, postgres and sqlite
+// drivers are registered by this package. If RegisterIntrospector is called twice with the same name or if factory
+// is nil, it panics.
+func RegisterIntrospector(driver string, factory IntrospectorFactory) {
+	introspectorsMu.Lock()
+	defer introspectorsMu.Unlock()
+	if factory == nil {
+		panic("db2struct: RegisterIntrospector factory is nil")
+	}
+	if _, dup := introspectors[driver]; dup {
+		panic("db2struct: RegisterIntrospector called twice for driver " + driver)
+	}
+	introspectors[driver] = factory
+}
+
+// Introspectors returns the names of the registered drivers in name order
+func Introspectors() []string {
+	introspectorsMu.RLock()
+	defer introspectorsMu.RUnlock()
+	drivers := make([]string, 0, len(introspectors))
+	for driver := range introspectors {
+		drivers = append(drivers, driver)
+	}
+	sort.Strings(drivers)
+	return drivers
+}
+
+// NewIntrospector connects to a database with the Introspector registered for the driver
+func NewIntrospector(driver string, config ConnectionConfig) (Introspector, error) {
+	introspectorsMu.RLock()
+	factory, ok := introspectors[driver]
+	introspectorsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown driver %q (forgotten import?)", driver)
+	}
+	return factory(config)
+}
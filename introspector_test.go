@@ -0,0 +1,74 @@
+package db2struct
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testIntrospector describes a fixed list of tables
+type testIntrospector struct {
+	tables []*Table
+	closed bool
+}
+
+func (i *testIntrospector) ListTables() ([]string, error) {
+	names := []string{}
+	for _, table := range i.tables {
+		names = append(names, table.Name)
+	}
+	return names, nil
+}
+
+func (i *testIntrospector) DescribeTable(table string) (*Table, error) {
+	for _, t := range i.tables {
+		if t.Name == table {
+			return t, nil
+		}
+	}
+	return &Table{Name: table}, nil
+}
+
+func (i *testIntrospector) DescribeSchema() ([]*Table, error) {
+	return i.tables, nil
+}
+
+func (i *testIntrospector) Close() error {
+	i.closed = true
+	return nil
+}
+
+func TestRegisterIntrospector(t *testing.T) {
+	var config ConnectionConfig
+	RegisterIntrospector("test", func(c ConnectionConfig) (Introspector, error) {
+		config = c
+		return &testIntrospector{tables: []*Table{{Name: "users"}}}, nil
+	})
+
+	introspector, err := NewIntrospector("test", ConnectionConfig{Database: "example"})
+	Convey("Should be able to connect with a registered introspector", t, func() {
+		So(err, ShouldBeNil)
+		So(config.Database, ShouldEqual, "example")
+		tables, err := introspector.ListTables()
+		So(err, ShouldBeNil)
+		So(tables, ShouldResemble, []string{"users"})
+	})
+
+	Convey("Should list the registered drivers", t, func() {
+		So(Introspectors(), ShouldResemble, []string{DialectMysql, DialectPostgres, DialectSqlite, "test"})
+	})
+
+	_, err = NewIntrospector("unknown", ConnectionConfig{})
+	Convey("Should get an error for an unknown driver", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Should panic registering a driver twice", t, func() {
+		So(func() {
+			RegisterIntrospector(DialectMysql, newMysqlIntrospector)
+		}, ShouldPanic)
+		So(func() {
+			RegisterIntrospector("nil", nil)
+		}, ShouldPanic)
+	})
+}
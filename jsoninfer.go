@@ -0,0 +1,227 @@
+package db2struct
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of the values of a json shape
+const (
+	jsonNull = iota
+	jsonBool
+	jsonInt
+	jsonFloat
+	jsonString
+	jsonObject
+	jsonArray
+	jsonMixed
+)
+
+// jsonShape is the shape of json values, merged from every sample of a column
+type jsonShape struct {
+	kind int
+	// nullable is set if some of the values are null
+	nullable bool
+	// count is the number of objects merged into an object shape
+	count int
+	// fields are the shapes of the fields of objects, fieldCounts the number of objects which have them
+	fields      map[string]*jsonShape
+	fieldCounts map[string]int
+	// elem is the shape of the elements of arrays, nil if every array is empty
+	elem *jsonShape
+}
+
+// inferJSONShape returns the shape of the json samples
+func inferJSONShape(samples []string) (*jsonShape, error) {
+	var shape *jsonShape
+	for _, sample := range samples {
+		decoder := json.NewDecoder(bytes.NewReader([]byte(sample)))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		shape = mergeJSONShapes(shape, jsonShapeOf(value))
+	}
+	return shape, nil
+}
+
+// jsonShapeOf returns the shape of a decoded json value
+func jsonShapeOf(value interface{}) *jsonShape {
+	switch v := value.(type) {
+	case bool:
+		return &jsonShape{kind: jsonBool}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &jsonShape{kind: jsonInt}
+		}
+		return &jsonShape{kind: jsonFloat}
+	case string:
+		return &jsonShape{kind: jsonString}
+	case []interface{}:
+		shape := &jsonShape{kind: jsonArray}
+		for _, elem := range v {
+			shape.elem = mergeJSONShapes(shape.elem, jsonShapeOf(elem))
+		}
+		return shape
+	case map[string]interface{}:
+		shape := &jsonShape{kind: jsonObject, count: 1, fields: map[string]*jsonShape{}, fieldCounts: map[string]int{}}
+		for key, field := range v {
+			shape.fields[key] = jsonShapeOf(field)
+			shape.fieldCounts[key] = 1
+		}
+		return shape
+	}
+	return &jsonShape{kind: jsonNull, nullable: true}
+}
+
+// mergeJSONShapes returns the shape of the values of both shapes, numbers are merged into floats and values of
+// different kinds into mixed values
+func mergeJSONShapes(a *jsonShape, b *jsonShape) *jsonShape {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.kind == jsonNull:
+		b.nullable = true
+		return b
+	case b.kind == jsonNull:
+		a.nullable = true
+		return a
+	}
+
+	merged := &jsonShape{kind: a.kind, nullable: a.nullable || b.nullable}
+	switch {
+	case a.kind == jsonObject && b.kind == jsonObject:
+		merged.count = a.count + b.count
+		merged.fields, merged.fieldCounts = a.fields, a.fieldCounts
+		for key, field := range b.fields {
+			merged.fields[key] = mergeJSONShapes(merged.fields[key], field)
+			merged.fieldCounts[key] += b.fieldCounts[key]
+		}
+	case a.kind == jsonArray && b.kind == jsonArray:
+		merged.elem = mergeJSONShapes(a.elem, b.elem)
+	case a.kind == b.kind:
+	case (a.kind == jsonInt || a.kind == jsonFloat) && (b.kind == jsonInt || b.kind == jsonFloat):
+		merged.kind = jsonFloat
+	default:
+		merged.kind = jsonMixed
+	}
+	return merged
+}
+
+// jsonType is a struct, or slice of structs, inferred from the samples of a json column with Scan and Value methods
+// which (de)serialize it
+type jsonType struct {
+	name   string
+	table  string
+	column string
+	shape  *jsonShape
+}
+
+// columnJSONType returns the type of a json column with the given name inferred from its samples, if the samples are
+// objects or arrays
+func columnJSONType(table *Table, column *Column, name string) (jsonType, bool, error) {
+	if !column.isJSON() || len(column.Samples) == 0 {
+		return jsonType{}, false, nil
+	}
+	shape, err := inferJSONShape(column.Samples)
+	if err != nil {
+		return jsonType{}, false, fmt.Errorf("invalid json sample of column %s of table %s: %s", column.Name, table.Name, err)
+	}
+	if shape == nil || (shape.kind != jsonObject && shape.kind != jsonArray) {
+		return jsonType{}, false, nil
+	}
+	return jsonType{name: name, table: table.Name, column: column.Name, shape: shape}, true, nil
+}
+
+// generate returns the unformatted declarations of the inferred types and the Scan and Value methods, and adds the
+// imports they require
+func (j jsonType) generate(imports map[string]bool) string {
+	imports["database/sql/driver"] = true
+	imports["encoding/json"] = true
+	imports["fmt"] = true
+
+	// the struct, or slice of structs, of the column is declared first
+	comment := fmt.Sprintf("// %%s is inferred from the samples of the %s json column of the %s table\n", j.column, j.table)
+	decls := []string{}
+	if j.shape.kind == jsonObject {
+		j.goType(j.shape, j.name, comment, &decls)
+	} else {
+		decls = append(decls, "")
+		sliceType := j.goType(j.shape, j.name, comment, &decls)
+		decls[0] = fmt.Sprintf(comment+"type %s %s", j.name, j.name, sliceType)
+	}
+
+	receiver := strings.ToLower(j.name[:1])
+	src := "// Scan implements the sql.Scanner interface\n"
+	src += fmt.Sprintf("func (%s *%s) Scan(value interface{}) error {\n", receiver, j.name)
+	src += fmt.Sprintf("switch v := value.(type) {\ncase string:\nreturn json.Unmarshal([]byte(v), %s)\n", receiver)
+	src += fmt.Sprintf("case []byte:\nreturn json.Unmarshal(v, %s)\n}\n", receiver)
+	src += fmt.Sprintf("return fmt.Errorf(\"cannot scan %%T into %s\", value)\n}\n\n", j.name)
+
+	src += "// Value implements the driver.Valuer interface\n"
+	src += fmt.Sprintf("func (%s %s) Value() (driver.Value, error) {\n", receiver, j.name)
+	src += fmt.Sprintf("value, err := json.Marshal(%s)\nreturn string(value), err\n}", receiver)
+
+	decls = append(decls[:1], append([]string{src}, decls[1:]...)...)
+	return strings.Join(decls, "\n\n")
+}
+
+// goType returns the go type of a shape, objects are declared as structs with the given name in decls
+func (j jsonType) goType(shape *jsonShape, name string, comment string, decls *[]string) string {
+	if shape == nil {
+		return "interface{}"
+	}
+	switch shape.kind {
+	case jsonBool:
+		return golangBool
+	case jsonInt:
+		return golangInt64
+	case jsonFloat:
+		return golangFloat64
+	case jsonString:
+		return "string"
+	case jsonArray:
+		return "[]" + j.goType(shape.elem, name+"Item", comment, decls)
+	case jsonObject:
+		// declare the struct before the structs of its fields
+		index := len(*decls)
+		*decls = append(*decls, "")
+
+		keys := make([]string, 0, len(shape.fields))
+		for key := range shape.fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		src := fmt.Sprintf(comment+"type %s struct {", name, name)
+		seen := map[string]bool{}
+		for _, key := range keys {
+			fieldName := identifierName(key)
+			if seen[fieldName] {
+				fieldName += fmt.Sprintf("%d", len(seen))
+			}
+			seen[fieldName] = true
+
+			field := shape.fields[key]
+			fieldType := j.goType(field, name+fieldName, comment, decls)
+			tag := key
+			if field.nullable || shape.fieldCounts[key] < shape.count {
+				// optional fields are omitted when empty and pointers unless nil already is empty
+				tag += ",omitempty"
+				if fieldType != "interface{}" && !strings.HasPrefix(fieldType, "[]") {
+					fieldType = "*" + fieldType
+				}
+			}
+			src += fmt.Sprintf("\n%s %s `json:%q`", fieldName, fieldType, tag)
+		}
+		(*decls)[index] = src + "\n}"
+		return name
+	}
+	return "interface{}"
+}
@@ -0,0 +1,124 @@
+package db2struct
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInferJSONShape(t *testing.T) {
+	shape, err := inferJSONShape([]string{
+		`{"id": 1, "price": 1, "name": "a", "tags": ["x"], "extra": null}`,
+		`{"id": 2, "price": 2.5, "name": "b", "tags": [], "extra": 1}`,
+		`{"id": 3, "price": 3, "tags": ["y"], "extra": "c"}`,
+	})
+	Convey("Should merge the shapes of the samples", t, func() {
+		So(err, ShouldBeNil)
+		So(shape.kind, ShouldEqual, jsonObject)
+		So(shape.count, ShouldEqual, 3)
+		So(shape.fields["id"].kind, ShouldEqual, jsonInt)
+		So(shape.fields["price"].kind, ShouldEqual, jsonFloat)
+		So(shape.fieldCounts["name"], ShouldEqual, 2)
+		So(shape.fields["tags"].elem.kind, ShouldEqual, jsonString)
+		So(shape.fields["extra"].kind, ShouldEqual, jsonMixed)
+		So(shape.fields["extra"].nullable, ShouldBeTrue)
+	})
+
+	_, err = inferJSONShape([]string{`{"id": `})
+	Convey("Should get an error for invalid json", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestJSONInferGenerate(t *testing.T) {
+	expectedStruct :=
+		`package test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type Events struct {
+	ID      int32
+	Payload EventsPayload
+	Tags    *EventsTags
+	Raw     string
+}
+
+// EventsPayload is inferred from the samples of the payload json column of the events table
+type EventsPayload struct {
+	Address *EventsPayloadAddress    ` + "`json:\"address,omitempty\"`" + `
+	Items   []EventsPayloadItemsItem ` + "`json:\"items\"`" + `
+	UserID  int64                    ` + "`json:\"userId\"`" + `
+}
+
+// Scan implements the sql.Scanner interface
+func (e *EventsPayload) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	case []byte:
+		return json.Unmarshal(v, e)
+	}
+	return fmt.Errorf("cannot scan %T into EventsPayload", value)
+}
+
+// Value implements the driver.Valuer interface
+func (e EventsPayload) Value() (driver.Value, error) {
+	value, err := json.Marshal(e)
+	return string(value), err
+}
+
+// EventsPayloadAddress is inferred from the samples of the payload json column of the events table
+type EventsPayloadAddress struct {
+	City string ` + "`json:\"city\"`" + `
+}
+
+// EventsPayloadItemsItem is inferred from the samples of the payload json column of the events table
+type EventsPayloadItemsItem struct {
+	Qty int64 ` + "`json:\"qty\"`" + `
+}
+
+// EventsTags is inferred from the samples of the tags json column of the events table
+type EventsTags []string
+
+// Scan implements the sql.Scanner interface
+func (e *EventsTags) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	case []byte:
+		return json.Unmarshal(v, e)
+	}
+	return fmt.Errorf("cannot scan %T into EventsTags", value)
+}
+
+// Value implements the driver.Valuer interface
+func (e EventsTags) Value() (driver.Value, error) {
+	value, err := json.Marshal(e)
+	return string(value), err
+}
+`
+	table := &Table{Name: "events", Columns: []*Column{
+		{Name: "id", DataType: "int", ColumnType: "int(11)", Key: KeyPrimary},
+		{Name: "payload", DataType: "json", ColumnType: "json", Samples: []string{
+			`{"userId": 1, "address": {"city": "x"}, "items": [{"qty": 1}]}`,
+			`{"userId": 2, "items": []}`,
+		}},
+		{Name: "tags", DataType: "json", ColumnType: "json", Nullable: true, Samples: []string{`["a"]`}},
+		{Name: "raw", DataType: "json", ColumnType: "json", Samples: []string{`"a"`}},
+	}}
+	bytes, err := GenerateStruct(table, "", GenerateOptions{PackageName: "test"})
+	Convey("Should be able to generate the structs inferred from json samples", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+
+	table.Columns[1].Samples = []string{"{"}
+	_, err = GenerateStruct(table, "", GenerateOptions{PackageName: "test"})
+	Convey("Should get an error for invalid json samples", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,218 @@
+package db2struct
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tags which can be added to the struct fields, more can be registered with RegisterTagEmitter
+const (
+	// TagJSON adds json:"column" tags, with omitempty if requested
+	TagJSON = "json"
+	// TagGorm adds GORM v2 gorm:"column:column" tags, with the type, size, primaryKey, autoIncrement, not null,
+	// default, read only, autoUpdateTime, index, uniqueIndex and comment settings
+	TagGorm = "gorm"
+	// TagDB adds db:"column" tags for sqlx and scany
+	TagDB = "db"
+	// TagBun adds bun:"column" tags, with pk, autoincrement, notnull and scanonly
+	TagBun = "bun"
+	// TagXorm adds xorm:"'column'" tags, with pk, autoincr, read only, updated and notnull or null
+	TagXorm = "xorm"
+	// TagPg adds go-pg pg:"column" tags, with pk and notnull
+	TagPg = "pg"
+	// TagSQL adds sql:"column" tags of older go-pg versions, with pk and notnull
+	TagSQL = "sql"
+	// TagYAML adds yaml:"column" tags, with omitempty if requested
+	TagYAML = "yaml"
+	// TagTOML adds toml:"column" tags, with omitempty if requested
+	TagTOML = "toml"
+	// TagXML adds xml:"column" tags, with omitempty if requested
+	TagXML = "xml"
+	// TagBSON adds bson:"column" tags, with omitempty if requested
+	TagBSON = "bson"
+	// TagMsgpack adds msgpack:"column" tags, with omitempty if requested
+	TagMsgpack = "msgpack"
+	// TagMapstructure adds mapstructure:"column" tags, with omitempty if requested
+	TagMapstructure = "mapstructure"
+)
+
+// Types used for nullable columns
+const (
+	// NullTypesSQL uses the database/sql NullX types, such as sql.NullString
+	NullTypesSQL = "sql"
+	// NullTypesGuregu uses the guregu (https://github.com/guregu/null) null.X types, such as null.String
+	NullTypesGuregu = "guregu"
+	// NullTypesPointer uses pointers, such as *string and *time.Time, so that JSON null round-trips. []byte columns
+	// are not wrapped as a nil slice already is NULL.
+	NullTypesPointer = "pointer"
+	// NullTypesGeneric uses a generic type, sql.Null[T] (Go 1.22+) unless GenericNullType is set
+	NullTypesGeneric = "generic"
+)
+
+// defaultGenericNullType is the generic type used by NullTypesGeneric if no GenericNullType is set
+const defaultGenericNullType = "database/sql.Null"
+
+// GenerateOptions configures the structs generated by GenerateStruct and GenerateStructs
+//
+// The zero value generates structs without tags or methods, using the database/sql types for nullable columns.
+type GenerateOptions struct {
+	// PackageName is the name of the package of the generated file
+	PackageName string
+	// Tags added to every field, in order, such as TagGorm and TagJSON, or tags registered with RegisterTagEmitter
+	Tags []string
+	// OmitEmpty adds omitempty to the encoding tags of nullable columns, such as json and yaml
+	OmitEmpty bool
+	// NullTypes is NullTypesSQL, NullTypesGuregu, NullTypesPointer or NullTypesGeneric, NullTypesSQL if empty
+	NullTypes string
+	// GenericNullType is the generic type of nullable columns with NullTypesGeneric qualified by its import path, such
+	// as github.com/samber/mo.Option. sql.Null if empty.
+	GenericNullType string
+	// FieldName returns the field name of a column, if nil field names are formatted the way golint expects
+	FieldName func(column string) string
+	// StructName returns the struct name of a table when no struct name is given, StructNameFromTable if nil
+	StructName func(table string) string
+	// HeaderComment is added as a comment above the package clause, such as "Code generated by db2struct. DO NOT EDIT."
+	HeaderComment string
+	// TableNameMethod adds a TableName method returning the name of the table to every struct
+	TableNameMethod bool
+	// PrimaryKeyMethod adds a PrimaryKey method returning the primary key to the structs of tables with a primary key.
+	// The key of a composite primary key is a comparable struct of the key columns in key order, named after the
+	// struct with a Key suffix, such as OrderItemsKey. []byte columns are strings in the key struct, composite keys
+	// with columns of other types which are not comparable, such as pq arrays, get no PrimaryKey method.
+	PrimaryKeyMethod bool
+	// Constructor adds a New function returning a struct with the literal column defaults of the table, such as
+	// NewUsers. Defaults of expressions, such as CURRENT_TIMESTAMP, are left to the database.
+	Constructor bool
+	// Relations adds association fields for the foreign keys of the table, a pointer to the referenced struct, and
+	// for the foreign keys referencing it, a slice of the referencing structs, with gorm and bun relation tags
+	Relations bool
+	// JoinTables are many-to-many join tables, such as returned by JoinTables. The structs of the tables they join get
+	// a slice of each other with gorm many2many tags, and GenerateStructs does not generate the join tables themselves.
+	JoinTables []*Table
+	// TypeMap overrides the go types of columns by table and column name, column type or data type
+	TypeMap TypeMap
+	// FallbackType is used for columns of unknown data types, such as []byte or interface{}. If empty, generating a
+	// struct with a column of an unknown data type fails.
+	FallbackType string
+}
+
+// legacyGenerateOptions returns the options matching the boolean parameters of Generate
+func legacyGenerateOptions(pkgName string, jsonAnnotation bool, gormAnnotation bool, gureguTypes bool) GenerateOptions {
+	options := GenerateOptions{PackageName: pkgName, TableNameMethod: gormAnnotation}
+	if gormAnnotation {
+		options.Tags = append(options.Tags, TagGorm)
+	}
+	if jsonAnnotation {
+		options.Tags = append(options.Tags, TagJSON)
+	}
+	if gureguTypes {
+		options.NullTypes = NullTypesGuregu
+	}
+	return options
+}
+
+// validate checks the tags and null types of the options
+func (o *GenerateOptions) validate() error {
+	for _, tag := range o.Tags {
+		if _, ok := tagEmitter(tag); !ok {
+			return fmt.Errorf("unknown tag %q", tag)
+		}
+	}
+	switch o.NullTypes {
+	case "", NullTypesSQL, NullTypesGuregu, NullTypesPointer, NullTypesGeneric:
+	default:
+		return fmt.Errorf("unknown null types %q", o.NullTypes)
+	}
+	return nil
+}
+
+// wrapsNullable reports whether the types of nullable columns are derived from the type of the not null column
+func (o *GenerateOptions) wrapsNullable() bool {
+	return o.NullTypes == NullTypesPointer || o.NullTypes == NullTypesGeneric
+}
+
+// nullableType returns the type of a nullable column of the given go type and adds the imports it requires. Types are
+// only wrapped with NullTypesPointer and NullTypesGeneric.
+func (o *GenerateOptions) nullableType(goType string, imports map[string]bool) string {
+	switch o.NullTypes {
+	case NullTypesPointer:
+		if goType == golangByteArray || strings.HasPrefix(goType, "*") {
+			return goType
+		}
+		return "*" + goType
+	case NullTypesGeneric:
+		genericType := o.GenericNullType
+		if genericType == "" {
+			genericType = defaultGenericNullType
+		}
+		generic := parseGoType(genericType)
+		if generic.Import != "" {
+			imports[generic.Import] = true
+		}
+		return generic.Type + "[" + goType + "]"
+	}
+	return goType
+}
+
+// nullableFieldType returns the field type of a nullable column of the given go type, which always holds NULL. Types
+// are wrapped with NullTypesPointer and NullTypesGeneric. Otherwise types which already hold NULL, such as
+// sql.NullString or []byte, are kept and other types are pointers, as database/sql and guregu have no null type for
+// them.
+func (o *GenerateOptions) nullableFieldType(goType string, imports map[string]bool) string {
+	if o.wrapsNullable() {
+		return o.nullableType(goType, imports)
+	}
+	if holdsNull(goType) {
+		return goType
+	}
+	return "*" + goType
+}
+
+// holdsNull reports whether a go type can hold NULL: pointers, slices, maps, interfaces and the database/sql, guregu,
+// pq and json.RawMessage types
+func holdsNull(goType string) bool {
+	for _, prefix := range []string{"*", "[]", "map[", "sql.Null", "null.", "pq."} {
+		if strings.HasPrefix(goType, prefix) {
+			return true
+		}
+	}
+	return goType == "interface{}" || goType == "any" || goType == "json.RawMessage"
+}
+
+// IsJoinTable reports whether the table is one of the JoinTables, whose structs are not generated by GenerateStructs
+func (o *GenerateOptions) IsJoinTable(table *Table) bool {
+	for _, join := range o.JoinTables {
+		if join == table {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldName returns the field name of a column
+func (o *GenerateOptions) fieldName(column string) string {
+	if o.FieldName != nil {
+		return o.FieldName(column)
+	}
+	return fmtFieldName(stringifyFirstChar(column))
+}
+
+// structName returns the struct name of a table
+func (o *GenerateOptions) structName(table string) string {
+	if o.StructName != nil {
+		return o.StructName(table)
+	}
+	return StructNameFromTable(table)
+}
+
+// header returns the header comment and package clause of the generated file
+func (o *GenerateOptions) header() string {
+	src := ""
+	if o.HeaderComment != "" {
+		for _, line := range strings.Split(strings.TrimRight(o.HeaderComment, "\n"), "\n") {
+			src += strings.TrimRight("// "+line, " ") + "\n"
+		}
+		src += "\n"
+	}
+	return src + fmt.Sprintf("package %s\n", o.PackageName)
+}
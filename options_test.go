@@ -0,0 +1,188 @@
+package db2struct
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenerateStructOptions(t *testing.T) {
+	expectedStruct :=
+		`// Code generated by db2struct. DO NOT EDIT.
+
+package test
+
+import "gopkg.in/guregu/null.v4"
+
+type Users struct {
+	id   int32       ` + "`json:\"id\" gorm:\"column:id;primaryKey\"`" + `
+	name null.String ` + "`json:\"name\" gorm:\"column:name\"`" + `
+}
+
+// TableName sets the insert table name for this struct type
+func (u *Users) TableName() string {
+	return "users"
+}
+`
+	table := &Table{Name: "users", Columns: []*Column{
+		{Name: "id", DataType: "int", Key: KeyPrimary},
+		{Name: "name", DataType: "varchar", Nullable: true},
+	}}
+	bytes, err := GenerateStruct(table, "", GenerateOptions{
+		PackageName:     "test",
+		Tags:            []string{TagJSON, TagGorm},
+		NullTypes:       NullTypesGuregu,
+		FieldName:       strings.ToLower,
+		HeaderComment:   "Code generated by db2struct. DO NOT EDIT.",
+		TableNameMethod: true,
+	})
+	Convey("Should be able to generate a struct configured by options", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+
+	bytes, err = GenerateStructs([]*Table{table, {Name: "teams"}}, GenerateOptions{
+		PackageName: "test",
+		StructName:  func(table string) string { return "Model" + StructNameFromTable(table) },
+	})
+	Convey("Should name structs with the struct name option", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldContainSubstring, "type ModelUsers struct")
+		So(string(bytes), ShouldContainSubstring, "type ModelTeams struct")
+		So(string(bytes), ShouldContainSubstring, "Name sql.NullString\n")
+		So(string(bytes), ShouldNotContainSubstring, "TableName")
+	})
+
+	_, err = GenerateStruct(table, "", GenerateOptions{PackageName: "test", Tags: []string{"protobuf"}})
+	Convey("Should get an error for an unknown tag", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+
+	_, err = GenerateStruct(table, "", GenerateOptions{PackageName: "test", NullTypes: "optional"})
+	Convey("Should get an error for unknown null types", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestLegacyGenerateOptions(t *testing.T) {
+	options := legacyGenerateOptions("test", true, true, true)
+	Convey("Should convert the boolean parameters of Generate to options", t, func() {
+		So(options.PackageName, ShouldEqual, "test")
+		So(options.Tags, ShouldResemble, []string{TagGorm, TagJSON})
+		So(options.NullTypes, ShouldEqual, NullTypesGuregu)
+		So(options.TableNameMethod, ShouldBeTrue)
+	})
+}
+
+func TestNullTypesGenerate(t *testing.T) {
+	table := &Table{Name: "users", Columns: []*Column{
+		{Name: "id", DataType: "int", ColumnType: "int(11)", Key: KeyPrimary},
+		{Name: "name", DataType: "varchar", ColumnType: "varchar(255)", Nullable: true},
+		{Name: "logins", DataType: "int", ColumnType: "int(11)", Nullable: true},
+		{Name: "deleted", DataType: "datetime", ColumnType: "datetime", Nullable: true},
+		{Name: "avatar", DataType: "blob", ColumnType: "blob", Nullable: true},
+		{Name: "price", DataType: "decimal", ColumnType: "decimal(10,2)", Nullable: true},
+	}}
+	typeMap := TypeMap{"decimal": {Type: "decimal.Decimal", Import: "github.com/shopspring/decimal"}}
+
+	expectedPointers :=
+		`package test
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Users struct {
+	ID      int32
+	Name    *string
+	Logins  *int32
+	Deleted *time.Time
+	Avatar  []byte
+	Price   *decimal.Decimal
+}
+`
+	bytes, err := GenerateStruct(table, "", GenerateOptions{PackageName: "test", NullTypes: NullTypesPointer, TypeMap: typeMap})
+	Convey("Should be able to generate pointers for nullable columns", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedPointers)
+	})
+
+	expectedGeneric :=
+		`package test
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Users struct {
+	ID      int32
+	Name    sql.Null[string]
+	Logins  sql.Null[int32]
+	Deleted sql.Null[time.Time]
+	Avatar  sql.Null[[]byte]
+	Price   sql.Null[decimal.Decimal]
+}
+`
+	bytes, err = GenerateStruct(table, "", GenerateOptions{PackageName: "test", NullTypes: NullTypesGeneric, TypeMap: typeMap})
+	Convey("Should be able to generate sql.Null for nullable columns", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedGeneric)
+	})
+
+	bytes, err = GenerateStruct(table, "", GenerateOptions{
+		PackageName:     "test",
+		NullTypes:       NullTypesGeneric,
+		GenericNullType: "github.com/samber/mo.Option",
+	})
+	Convey("Should be able to generate a configured generic type for nullable columns", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldContainSubstring, "\t\"github.com/samber/mo\"\n")
+		So(string(bytes), ShouldContainSubstring, "Deleted mo.Option[time.Time]\n")
+		So(string(bytes), ShouldContainSubstring, "Price   mo.Option[float64]\n")
+	})
+}
+
+func TestNullableFieldTypes(t *testing.T) {
+	typeMap := TypeMap{"uuid": {Type: "uuid.UUID", Import: "github.com/google/uuid"}}
+	tables := []*Table{
+		{Name: "users", Dialect: DialectMysql, Columns: []*Column{
+			{Name: "id", DataType: "bigint", ColumnType: "bigint(20) unsigned", Nullable: true},
+			{Name: "token", DataType: "uuid", ColumnType: "uuid", Nullable: true},
+			{Name: "area", DataType: "polygon", ColumnType: "polygon", Nullable: true},
+		}},
+		{Name: "users", Dialect: DialectPostgres, Columns: []*Column{
+			{Name: "created", DataType: "timestamptz", ColumnType: "timestamp with time zone", Nullable: true},
+			{Name: "token", DataType: "uuid", ColumnType: "uuid", Nullable: true},
+			{Name: "area", DataType: "polygon", ColumnType: "polygon", Nullable: true},
+		}},
+		{Name: "users", Dialect: DialectSqlite, Columns: []*Column{
+			{Name: "created", DataType: "DATETIME", ColumnType: "DATETIME", Nullable: true},
+			{Name: "token", DataType: "UUID", ColumnType: "UUID", Nullable: true},
+		}},
+	}
+	Convey("Should never generate a type which can not hold NULL for a nullable column", t, func() {
+		for _, nullTypes := range []string{NullTypesSQL, NullTypesGuregu} {
+			for _, table := range tables {
+				options := &GenerateOptions{NullTypes: nullTypes, TypeMap: typeMap, FallbackType: "string"}
+				_, _, fieldTypes, err := generateTypes(table, "Users", 0, options, map[string]bool{})
+				So(err, ShouldBeNil)
+				for _, column := range table.Columns {
+					So(holdsNull(fieldTypes[column.Name]), ShouldBeTrue)
+				}
+			}
+		}
+	})
+
+	Convey("Should keep types which hold NULL and make pointers of other types", t, func() {
+		options := &GenerateOptions{}
+		So(options.nullableFieldType("sql.NullTime", nil), ShouldEqual, "sql.NullTime")
+		So(options.nullableFieldType(golangByteArray, nil), ShouldEqual, golangByteArray)
+		So(options.nullableFieldType("uint64", nil), ShouldEqual, "*uint64")
+	})
+}
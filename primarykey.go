@@ -0,0 +1,73 @@
+package db2struct
+
+import (
+	"fmt"
+	"strings"
+)
+
+// primaryKey is the PrimaryKey method of a struct, with the key struct of composite primary keys
+type primaryKey struct {
+	table      *Table
+	structName string
+	// fields and types are the field names and go types of the key columns in key order
+	fields []string
+	types  []string
+}
+
+// tablePrimaryKey returns the PrimaryKey method of the struct of a table, false if the table has no primary key or
+// if a column of a composite key has a type which is not comparable, such as a pq array
+func tablePrimaryKey(table *Table, structName string, fieldTypes map[string]string, options *GenerateOptions) (*primaryKey, bool) {
+	columns := table.PrimaryKey()
+	if len(columns) == 0 {
+		return nil, false
+	}
+	key := &primaryKey{table: table, structName: structName}
+	for _, column := range columns {
+		goType := fieldTypes[column]
+		// the key struct of a composite key must be comparable, slices other than []byte can not be converted
+		if len(columns) > 1 && goType != golangByteArray && !isComparableType(goType) {
+			return nil, false
+		}
+		key.fields = append(key.fields, options.fieldName(column))
+		key.types = append(key.types, goType)
+	}
+	return key, true
+}
+
+// isComparableType reports whether a go type can be compared with ==, such as to be used as a map key. Slices, maps
+// and the pq array types are not comparable.
+func isComparableType(goType string) bool {
+	return !strings.HasPrefix(goType, "[]") && !strings.HasPrefix(goType, "map[") && !strings.HasPrefix(goType, "pq.")
+}
+
+// keyName returns the name of the key struct of a composite primary key
+func (k *primaryKey) keyName() string {
+	return k.structName + "Key"
+}
+
+// generate returns the key struct of composite primary keys and the PrimaryKey method, which returns the value of a
+// single column key or the key struct
+func (k *primaryKey) generate(imports map[string]bool) string {
+	receiver := strings.ToLower(string(k.structName[0]))
+	if len(k.fields) == 1 {
+		return fmt.Sprintf("// PrimaryKey returns the primary key of %s\nfunc (%s *%s) PrimaryKey() %s {\nreturn %s.%s\n}",
+			k.structName, receiver, k.structName, k.types[0], receiver, k.fields[0])
+	}
+
+	src := fmt.Sprintf("// %s is the primary key of the %s table\ntype %s struct {\n", k.keyName(), k.table.Name, k.keyName())
+	values := make([]string, len(k.fields))
+	for i, field := range k.fields {
+		if k.types[i] == golangByteArray {
+			// []byte columns, such as binary(16) uuids, are strings so that the key struct is comparable
+			src += fmt.Sprintf("%s string\n", field)
+			values[i] = fmt.Sprintf("%s: string(%s.%s)", field, receiver, field)
+			continue
+		}
+		src += fmt.Sprintf("%s %s\n", field, k.types[i])
+		values[i] = fmt.Sprintf("%s: %s.%s", field, receiver, field)
+	}
+	src += "}\n\n"
+	src += fmt.Sprintf("// PrimaryKey returns the primary key of %s\nfunc (%s *%s) PrimaryKey() %s {\nreturn %s{%s}\n}",
+		k.structName, receiver, k.structName, k.keyName(), k.keyName(), strings.Join(values, ", "))
+	return src
+}
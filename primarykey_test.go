@@ -0,0 +1,112 @@
+package db2struct
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPrimaryKeyGenerate(t *testing.T) {
+	expectedStruct :=
+		`package test
+
+type OrderItem struct {
+	LineNo  int16
+	OrderID int64
+	Sku     string
+}
+
+// OrderItemKey is the primary key of the order_items table
+type OrderItemKey struct {
+	OrderID int64
+	LineNo  int16
+}
+
+// PrimaryKey returns the primary key of OrderItem
+func (o *OrderItem) PrimaryKey() OrderItemKey {
+	return OrderItemKey{OrderID: o.OrderID, LineNo: o.LineNo}
+}
+`
+	table := &Table{Name: "order_items", Columns: []*Column{
+		{Name: "line_no", DataType: "smallint", Key: KeyPrimary},
+		{Name: "order_id", DataType: "bigint", Key: KeyPrimary},
+		{Name: "sku", DataType: "varchar"},
+	}, Indexes: []*Index{
+		{Name: "PRIMARY", Columns: []string{"order_id", "line_no"}, Unique: true, Primary: true},
+	}}
+	bytes, err := GenerateStruct(table, "OrderItem", GenerateOptions{PackageName: "test", PrimaryKeyMethod: true})
+	Convey("Should generate a key struct of the columns of a composite primary key in key order", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+
+	expectedStruct =
+		`package test
+
+type User struct {
+	ID    uint32
+	Email string
+}
+
+// PrimaryKey returns the primary key of User
+func (u *User) PrimaryKey() uint32 {
+	return u.ID
+}
+`
+	table = &Table{Name: "users", Columns: []*Column{
+		{Name: "id", DataType: "int", ColumnType: "int(10) unsigned", Key: KeyPrimary},
+		{Name: "email", DataType: "varchar"},
+	}}
+	bytes, err = GenerateStruct(table, "User", GenerateOptions{PackageName: "test", PrimaryKeyMethod: true})
+	Convey("Should return the value of a single column primary key", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+
+	expectedStruct =
+		`package test
+
+type Session struct {
+	TenantID int32
+	Token    []byte
+}
+
+// SessionKey is the primary key of the sessions table
+type SessionKey struct {
+	TenantID int32
+	Token    string
+}
+
+// PrimaryKey returns the primary key of Session
+func (s *Session) PrimaryKey() SessionKey {
+	return SessionKey{TenantID: s.TenantID, Token: string(s.Token)}
+}
+`
+	table = &Table{Name: "sessions", Columns: []*Column{
+		{Name: "tenant_id", DataType: "int", Key: KeyPrimary},
+		{Name: "token", DataType: "binary", ColumnType: "binary(16)", Key: KeyPrimary},
+	}}
+	bytes, err = GenerateStruct(table, "Session", GenerateOptions{PackageName: "test", PrimaryKeyMethod: true})
+	Convey("Should convert []byte columns to strings so that the key struct is comparable", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+
+	table = &Table{Name: "tagged", Dialect: DialectPostgres, Columns: []*Column{
+		{Name: "id", DataType: "int4", Key: KeyPrimary},
+		{Name: "tags", DataType: "_text", Key: KeyPrimary},
+	}}
+	bytes, err = GenerateStruct(table, "Tagged", GenerateOptions{PackageName: "test", PrimaryKeyMethod: true})
+	Convey("Should not generate a PrimaryKey method for composite keys with columns which are not comparable", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldContainSubstring, "pq.StringArray")
+		So(string(bytes), ShouldNotContainSubstring, "PrimaryKey")
+	})
+
+	table = &Table{Name: "events", Columns: []*Column{{Name: "payload", DataType: "text"}}}
+	bytes, err = GenerateStruct(table, "Event", GenerateOptions{PackageName: "test", PrimaryKeyMethod: true})
+	Convey("Should not generate a PrimaryKey method for tables without a primary key", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldNotContainSubstring, "PrimaryKey")
+	})
+}
@@ -0,0 +1,260 @@
+package db2struct
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// relationKind is the kind of association of a relation field
+type relationKind int
+
+const (
+	relationBelongsTo  relationKind = iota // pointer to the referenced struct
+	relationHasMany                        // slice of the referencing structs
+	relationManyToMany                     // slice of the structs joined by a join table
+)
+
+// relation is an association field generated for a foreign key, a belongs-to field on the struct of the referencing
+// table, a has-many field on the struct of the referenced table or a many-to-many field on the structs joined by a
+// join table
+type relation struct {
+	name       string
+	structName string
+	kind       relationKind
+	// foreignKey of the relation, for many-to-many relations the foreign key of the join table referencing the struct
+	foreignKey *ForeignKey
+	// join is the join table of many-to-many relations
+	join *Table
+	// joinReferences is the foreign key of the join table referencing the joined struct
+	joinReferences *ForeignKey
+}
+
+// JoinTables returns the many-to-many join tables among the tables, tables whose only columns are the columns of two
+// foreign keys and optionally timestamps, such as created_at
+func JoinTables(tables []*Table) []*Table {
+	var joinTables []*Table
+	for _, table := range tables {
+		if isJoinTable(table) {
+			joinTables = append(joinTables, table)
+		}
+	}
+	return joinTables
+}
+
+// isJoinTable reports whether the only columns of a table are the columns of its two foreign keys and timestamps
+func isJoinTable(table *Table) bool {
+	if len(table.ForeignKeys) != 2 {
+		return false
+	}
+	keyColumns := make(map[string]bool)
+	for _, foreignKey := range table.ForeignKeys {
+		for _, column := range foreignKey.Columns {
+			keyColumns[column] = true
+		}
+	}
+	for _, column := range table.Columns {
+		dataType := strings.ToLower(column.DataType)
+		if !keyColumns[column.Name] && !strings.Contains(dataType, "timestamp") && !strings.Contains(dataType, "datetime") {
+			return false
+		}
+	}
+	return true
+}
+
+// tableRelations returns the belongs-to relations of the foreign keys of a table followed by the has-many relations
+// of the foreign keys referencing it, if requested, and the many-to-many relations of the join tables of the options.
+// Field names are derived from the foreign key columns and tables and do not collide with the column fields of the
+// struct. Join tables are not referenced by has-many relations, as they have no struct.
+func tableRelations(table *Table, structName string, options *GenerateOptions) []*relation {
+	used := make(map[string]bool)
+	for _, column := range table.Columns {
+		used[options.fieldName(column.Name)] = true
+	}
+	tableStructName := func(name string) string {
+		if name == table.Name {
+			return structName
+		}
+		return options.structName(name)
+	}
+
+	joinTables := make(map[string]*Table)
+	for _, join := range options.JoinTables {
+		joinTables[join.Name] = join
+	}
+
+	var relations []*relation
+	if options.Relations {
+		relations = append(relations, belongsToRelations(table, used, tableStructName, options)...)
+		relations = append(relations, hasManyRelations(table, structName, used, joinTables, tableStructName, options)...)
+	}
+	for _, join := range options.JoinTables {
+		for i, foreignKey := range join.ForeignKeys {
+			if foreignKey.ReferencedTable != table.Name {
+				continue
+			}
+			joinReferences := join.ForeignKeys[1-i]
+			name := options.fieldName(joinReferences.ReferencedTable)
+			// self joins, such as friends of users, get a field per foreign key
+			if used[name] || joinReferences.ReferencedTable == table.Name {
+				name = options.fieldName(join.Name) + "By" + belongsToName(foreignKey, structName, options)
+			}
+			relations = append(relations, &relation{name: uniqueFieldName(name, used), structName: tableStructName(joinReferences.ReferencedTable),
+				kind: relationManyToMany, foreignKey: foreignKey, join: join, joinReferences: joinReferences})
+		}
+	}
+	return relations
+}
+
+// belongsToRelations returns the belongs-to relations of the foreign keys of a table
+func belongsToRelations(table *Table, used map[string]bool, tableStructName func(string) string, options *GenerateOptions) []*relation {
+	var relations []*relation
+	for _, foreignKey := range table.ForeignKeys {
+		referenced := tableStructName(foreignKey.ReferencedTable)
+		name := belongsToName(foreignKey, referenced, options)
+		if used[name] {
+			name = options.fieldName(foreignKey.Name)
+		}
+		relations = append(relations, &relation{name: uniqueFieldName(name, used), structName: referenced, foreignKey: foreignKey})
+	}
+	return relations
+}
+
+// hasManyRelations returns the has-many relations of the foreign keys referencing a table, except the foreign keys of
+// join tables
+func hasManyRelations(table *Table, structName string, used map[string]bool, joinTables map[string]*Table,
+	tableStructName func(string) string, options *GenerateOptions) []*relation {
+	var relations []*relation
+	referencing := make(map[string]int)
+	for _, foreignKey := range table.ReferencedBy {
+		referencing[foreignKey.Table]++
+	}
+	for _, foreignKey := range table.ReferencedBy {
+		if joinTables[foreignKey.Table] != nil {
+			continue
+		}
+		name := options.fieldName(foreignKey.Table)
+		// tables referencing the struct more than once, such as a sender and a recipient, get a field per foreign key
+		if used[name] || referencing[foreignKey.Table] > 1 || foreignKey.Table == table.Name {
+			name += "By" + belongsToName(foreignKey, structName, options)
+		}
+		relations = append(relations, &relation{name: uniqueFieldName(name, used), structName: tableStructName(foreignKey.Table),
+			kind: relationHasMany, foreignKey: foreignKey})
+	}
+	return relations
+}
+
+// belongsToName returns the name of the belongs-to field of a foreign key, the field name of a single column without
+// its ID suffix, such as Team for team_id, or the name of the referenced struct
+func belongsToName(foreignKey *ForeignKey, referenced string, options *GenerateOptions) string {
+	if len(foreignKey.Columns) == 1 {
+		name := options.fieldName(foreignKey.Columns[0])
+		if len(name) > 2 && strings.HasSuffix(name, "ID") {
+			return strings.TrimSuffix(name, "ID")
+		}
+	}
+	return referenced
+}
+
+// uniqueFieldName returns name, numbered if it is already used, and marks it as used
+func uniqueFieldName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// fieldType returns the type of the relation field, a pointer for belongs-to and a slice for other relations
+func (r *relation) fieldType() string {
+	if r.kind == relationBelongsTo {
+		return "*" + r.structName
+	}
+	return "[]" + r.structName
+}
+
+// tags returns the struct tag annotations of the relation field, only gorm and bun tags describe relations. Many-to-many
+// relations only get gorm tags, as bun requires a struct for the join table.
+func (r *relation) tags(options *GenerateOptions) []string {
+	var annotations []string
+	for _, tag := range options.Tags {
+		var value string
+		switch {
+		case tag == TagGorm:
+			value = r.gormTag(options)
+		case tag == TagBun && r.kind != relationManyToMany:
+			value = r.bunTag()
+		default:
+			continue
+		}
+		annotations = append(annotations, tag+":"+strconv.Quote(value))
+	}
+	return annotations
+}
+
+// gormTag returns the GORM v2 foreignKey and references settings of the relation, and the constraint setting of
+// belongs-to relations with referential actions. Many-to-many relations get the many2many setting with the join
+// table and the joinForeignKey and joinReferences settings with the fields of its columns.
+func (r *relation) gormTag(options *GenerateOptions) string {
+	fieldNames := func(columns []string) string {
+		names := make([]string, len(columns))
+		for i, column := range columns {
+			names[i] = options.fieldName(column)
+		}
+		return strings.Join(names, ",")
+	}
+	if r.kind == relationManyToMany {
+		return strings.Join([]string{
+			"many2many:" + r.join.Name,
+			"foreignKey:" + fieldNames(r.foreignKey.ReferencedColumns),
+			"joinForeignKey:" + fieldNames(r.foreignKey.Columns),
+			"references:" + fieldNames(r.joinReferences.ReferencedColumns),
+			"joinReferences:" + fieldNames(r.joinReferences.Columns),
+		}, ";")
+	}
+	settings := []string{
+		"foreignKey:" + fieldNames(r.foreignKey.Columns),
+		"references:" + fieldNames(r.foreignKey.ReferencedColumns),
+	}
+	if r.kind == relationBelongsTo {
+		var actions []string
+		if isReferentialAction(r.foreignKey.OnUpdate) {
+			actions = append(actions, "OnUpdate:"+r.foreignKey.OnUpdate)
+		}
+		if isReferentialAction(r.foreignKey.OnDelete) {
+			actions = append(actions, "OnDelete:"+r.foreignKey.OnDelete)
+		}
+		if len(actions) > 0 {
+			settings = append(settings, "constraint:"+strings.Join(actions, ","))
+		}
+	}
+	return strings.Join(settings, ";")
+}
+
+// bunTag returns the bun rel and join settings of the relation, with a join per column of composite keys
+func (r *relation) bunTag() string {
+	settings := []string{"rel:belongs-to"}
+	if r.kind == relationHasMany {
+		settings[0] = "rel:has-many"
+	}
+	for i, column := range r.foreignKey.Columns {
+		referenced := r.foreignKey.ReferencedColumns[i]
+		if r.kind == relationHasMany {
+			settings = append(settings, "join:"+referenced+"="+column)
+		} else {
+			settings = append(settings, "join:"+column+"="+referenced)
+		}
+	}
+	return strings.Join(settings, ",")
+}
+
+// isReferentialAction reports whether an ON DELETE or ON UPDATE action changes the referencing rows, NO ACTION and
+// RESTRICT only reject changes
+func isReferentialAction(action string) bool {
+	switch strings.ToUpper(action) {
+	case "", "NO ACTION", "RESTRICT":
+		return false
+	}
+	return true
+}
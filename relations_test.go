@@ -0,0 +1,186 @@
+package db2struct
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newTestRelationTables returns tables with a composite, a self referencing and two foreign keys to the same table
+func newTestRelationTables() []*Table {
+	teams := &Table{Name: "teams", Columns: []*Column{
+		{Name: "org_id", DataType: "int", Key: KeyPrimary},
+		{Name: "id", DataType: "int", Key: KeyPrimary},
+		{Name: "parent_id", DataType: "int", Nullable: true},
+	}}
+	users := &Table{Name: "users", Columns: []*Column{
+		{Name: "id", DataType: "int", Key: KeyPrimary},
+		{Name: "org_id", DataType: "int"},
+		{Name: "team_id", DataType: "int"},
+	}}
+	transfers := &Table{Name: "transfers", Columns: []*Column{
+		{Name: "id", DataType: "int", Key: KeyPrimary},
+		{Name: "sender_id", DataType: "int"},
+		{Name: "recipient_id", DataType: "int"},
+	}}
+	foreignKeys := []*ForeignKey{
+		{Name: "teams_parent", Table: "teams", Columns: []string{"parent_id"}, ReferencedTable: "teams",
+			ReferencedColumns: []string{"id"}, OnDelete: "SET NULL", OnUpdate: "NO ACTION"},
+		{Name: "users_team", Table: "users", Columns: []string{"org_id", "team_id"}, ReferencedTable: "teams",
+			ReferencedColumns: []string{"org_id", "id"}, OnDelete: "CASCADE", OnUpdate: "CASCADE"},
+		{Name: "transfers_sender", Table: "transfers", Columns: []string{"sender_id"}, ReferencedTable: "users",
+			ReferencedColumns: []string{"id"}, OnDelete: "RESTRICT", OnUpdate: "RESTRICT"},
+		{Name: "transfers_recipient", Table: "transfers", Columns: []string{"recipient_id"}, ReferencedTable: "users",
+			ReferencedColumns: []string{"id"}, OnDelete: "NO ACTION", OnUpdate: "NO ACTION"},
+	}
+	tables := []*Table{teams, users, transfers}
+	addForeignKeys(tables, foreignKeys)
+	return tables
+}
+
+func TestTableRelations(t *testing.T) {
+	tables := newTestRelationTables()
+	options := &GenerateOptions{Relations: true}
+
+	names := func(relations []*relation) []string {
+		var names []string
+		for _, relation := range relations {
+			names = append(names, relation.name+" "+relation.fieldType())
+		}
+		return names
+	}
+	Convey("Should name belongs-to fields after the column and has-many fields after the table", t, func() {
+		So(names(tableRelations(tables[0], "Teams", options)), ShouldResemble, []string{
+			"Parent *Teams", "TeamsByParent []Teams", "Users []Users",
+		})
+		So(names(tableRelations(tables[1], "Users", options)), ShouldResemble, []string{
+			"Teams *Teams", "TransfersBySender []Transfers", "TransfersByRecipient []Transfers",
+		})
+		So(names(tableRelations(tables[2], "Transfer", options)), ShouldResemble, []string{
+			"Sender *Users", "Recipient *Users",
+		})
+	})
+
+	tables[2].Columns = append(tables[2].Columns, &Column{Name: "sender", DataType: "varchar"})
+	Convey("Should name fields colliding with a column after the constraint", t, func() {
+		So(names(tableRelations(tables[2], "Transfer", options)), ShouldResemble, []string{
+			"TransfersSender *Users", "Recipient *Users",
+		})
+	})
+}
+
+func TestRelationsGenerate(t *testing.T) {
+	expectedStruct :=
+		`package test
+
+import "database/sql"
+
+type Teams struct {
+	OrgID         int32         ` + "`" + `gorm:"column:org_id;primaryKey;autoIncrement:false" bun:"org_id,pk"` + "`" + `
+	ID            int32         ` + "`" + `gorm:"column:id;primaryKey;autoIncrement:false" bun:"id,pk"` + "`" + `
+	ParentID      sql.NullInt64 ` + "`" + `gorm:"column:parent_id" bun:"parent_id"` + "`" + `
+	Parent        *Teams        ` + "`" + `gorm:"foreignKey:ParentID;references:ID;constraint:OnDelete:SET NULL" bun:"rel:belongs-to,join:parent_id=id"` + "`" + `
+	TeamsByParent []Teams       ` + "`" + `gorm:"foreignKey:ParentID;references:ID" bun:"rel:has-many,join:id=parent_id"` + "`" + `
+	Users         []Users       ` + "`" + `gorm:"foreignKey:OrgID,TeamID;references:OrgID,ID" bun:"rel:has-many,join:org_id=org_id,join:id=team_id"` + "`" + `
+}
+
+type Users struct {
+	ID                   int32       ` + "`" + `gorm:"column:id;primaryKey" bun:"id,pk"` + "`" + `
+	OrgID                int32       ` + "`" + `gorm:"column:org_id;not null" bun:"org_id,notnull"` + "`" + `
+	TeamID               int32       ` + "`" + `gorm:"column:team_id;not null" bun:"team_id,notnull"` + "`" + `
+	Teams                *Teams      ` + "`" + `gorm:"foreignKey:OrgID,TeamID;references:OrgID,ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" bun:"rel:belongs-to,join:org_id=org_id,join:team_id=id"` + "`" + `
+	TransfersBySender    []Transfers ` + "`" + `gorm:"foreignKey:SenderID;references:ID" bun:"rel:has-many,join:id=sender_id"` + "`" + `
+	TransfersByRecipient []Transfers ` + "`" + `gorm:"foreignKey:RecipientID;references:ID" bun:"rel:has-many,join:id=recipient_id"` + "`" + `
+}
+
+type Transfers struct {
+	ID          int32  ` + "`" + `gorm:"column:id;primaryKey" bun:"id,pk"` + "`" + `
+	SenderID    int32  ` + "`" + `gorm:"column:sender_id;not null" bun:"sender_id,notnull"` + "`" + `
+	RecipientID int32  ` + "`" + `gorm:"column:recipient_id;not null" bun:"recipient_id,notnull"` + "`" + `
+	Sender      *Users ` + "`" + `gorm:"foreignKey:SenderID;references:ID" bun:"rel:belongs-to,join:sender_id=id"` + "`" + `
+	Recipient   *Users ` + "`" + `gorm:"foreignKey:RecipientID;references:ID" bun:"rel:belongs-to,join:recipient_id=id"` + "`" + `
+}
+`
+
+	bytes, err := GenerateStructs(newTestRelationTables(), GenerateOptions{PackageName: "test", Tags: []string{TagGorm, TagBun}, Relations: true})
+	Convey("Should generate belongs-to and has-many fields with gorm and bun relation tags", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+
+	bytes, err = GenerateStructs(newTestRelationTables(), GenerateOptions{PackageName: "test", Tags: []string{TagJSON}})
+	Convey("Should only generate relation fields if requested", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldNotContainSubstring, "TransfersBySender")
+		So(string(bytes), ShouldNotContainSubstring, "*Teams")
+	})
+}
+
+func TestJoinTables(t *testing.T) {
+	tags := &Table{Name: "tags", Columns: []*Column{{Name: "id", DataType: "int", Key: KeyPrimary}}}
+	users := &Table{Name: "users", Columns: []*Column{{Name: "id", DataType: "int", Key: KeyPrimary}}}
+	userTags := &Table{Name: "user_tags", Columns: []*Column{
+		{Name: "user_id", DataType: "int", Key: KeyPrimary},
+		{Name: "tag_id", DataType: "int", Key: KeyPrimary},
+		{Name: "created_at", DataType: "timestamp"},
+	}}
+	friends := &Table{Name: "friends", Columns: []*Column{
+		{Name: "user_id", DataType: "int", Key: KeyPrimary},
+		{Name: "friend_id", DataType: "int", Key: KeyPrimary},
+	}}
+	ratings := &Table{Name: "ratings", Columns: []*Column{
+		{Name: "user_id", DataType: "int", Key: KeyPrimary},
+		{Name: "tag_id", DataType: "int", Key: KeyPrimary},
+		{Name: "score", DataType: "int"},
+	}}
+	tables := []*Table{friends, ratings, tags, userTags, users}
+	addForeignKeys(tables, []*ForeignKey{
+		{Name: "friends_user", Table: "friends", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+		{Name: "friends_friend", Table: "friends", Columns: []string{"friend_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+		{Name: "ratings_user", Table: "ratings", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+		{Name: "ratings_tag", Table: "ratings", Columns: []string{"tag_id"}, ReferencedTable: "tags", ReferencedColumns: []string{"id"}},
+		{Name: "user_tags_user", Table: "user_tags", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+		{Name: "user_tags_tag", Table: "user_tags", Columns: []string{"tag_id"}, ReferencedTable: "tags", ReferencedColumns: []string{"id"}},
+	})
+
+	Convey("Should detect tables of two foreign keys and timestamps as join tables", t, func() {
+		So(JoinTables(tables), ShouldResemble, []*Table{friends, userTags})
+	})
+
+	options := GenerateOptions{JoinTables: JoinTables(tables)}
+	Convey("Should report the join tables of the options", t, func() {
+		So(options.IsJoinTable(userTags), ShouldBeTrue)
+		So(options.IsJoinTable(ratings), ShouldBeFalse)
+	})
+
+	expectedStruct :=
+		`package test
+
+type Ratings struct {
+	UserID int32  ` + "`" + `gorm:"column:user_id;primaryKey;autoIncrement:false"` + "`" + `
+	TagID  int32  ` + "`" + `gorm:"column:tag_id;primaryKey;autoIncrement:false"` + "`" + `
+	Score  int32  ` + "`" + `gorm:"column:score;not null"` + "`" + `
+	User   *Users ` + "`" + `gorm:"foreignKey:UserID;references:ID"` + "`" + `
+	Tag    *Tags  ` + "`" + `gorm:"foreignKey:TagID;references:ID"` + "`" + `
+}
+
+type Tags struct {
+	ID      int32     ` + "`" + `gorm:"column:id;primaryKey"` + "`" + `
+	Ratings []Ratings ` + "`" + `gorm:"foreignKey:TagID;references:ID"` + "`" + `
+	Users   []Users   ` + "`" + `gorm:"many2many:user_tags;foreignKey:ID;joinForeignKey:TagID;references:ID;joinReferences:UserID"` + "`" + `
+}
+
+type Users struct {
+	ID              int32     ` + "`" + `gorm:"column:id;primaryKey"` + "`" + `
+	Ratings         []Ratings ` + "`" + `gorm:"foreignKey:UserID;references:ID"` + "`" + `
+	FriendsByUser   []Users   ` + "`" + `gorm:"many2many:friends;foreignKey:ID;joinForeignKey:UserID;references:ID;joinReferences:FriendID"` + "`" + `
+	FriendsByFriend []Users   ` + "`" + `gorm:"many2many:friends;foreignKey:ID;joinForeignKey:FriendID;references:ID;joinReferences:UserID"` + "`" + `
+	Tags            []Tags    ` + "`" + `gorm:"many2many:user_tags;foreignKey:ID;joinForeignKey:UserID;references:ID;joinReferences:TagID"` + "`" + `
+}
+`
+	bytes, err := GenerateStructs(tables, GenerateOptions{PackageName: "test", Tags: []string{TagGorm}, Relations: true, JoinTables: JoinTables(tables)})
+	Convey("Should generate many2many fields instead of structs for join tables", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+}
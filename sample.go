@@ -0,0 +1,64 @@
+package db2struct
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SamplingIntrospector is an Introspector which can read sample values of json columns, so that the struct of their
+// values can be inferred
+type SamplingIntrospector interface {
+	Introspector
+	// SampleJSONColumnsContext sets the Samples of the json columns of the table to at most limit non-null values
+	SampleJSONColumnsContext(ctx context.Context, table *Table, limit int) error
+}
+
+// SampleJSONColumnsContext sets the Samples of the json columns of a table to at most limit of their non-null values,
+// queried with q in the dialect of the table
+func SampleJSONColumnsContext(ctx context.Context, q Queryer, table *Table, limit int) error {
+	quote := quotePostgresIdentifier
+	if table.dialect() == DialectMysql {
+		quote = quoteMysqlIdentifier
+	}
+	from := quote(table.Name)
+	if table.Schema != "" {
+		from = quote(table.Schema) + "." + from
+	}
+
+	for _, column := range table.Columns {
+		if !column.isJSON() {
+			continue
+		}
+		name := quote(column.Name)
+		rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE %s IS NOT NULL LIMIT %d", name, from, name, limit))
+		if err != nil {
+			return fmt.Errorf("error sampling column %s of table %s: %s", column.Name, table.Name, err)
+		}
+		column.Samples = nil
+		for rows.Next() {
+			var sample string
+			if err = rows.Scan(&sample); err != nil {
+				rows.Close()
+				return err
+			}
+			column.Samples = append(column.Samples, sample)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quoteMysqlIdentifier quotes a mysql identifier with backticks
+func quoteMysqlIdentifier(identifier string) string {
+	return "`" + strings.Replace(identifier, "`", "``", -1) + "`"
+}
+
+// quotePostgresIdentifier quotes a postgres or sqlite identifier with double quotes
+func quotePostgresIdentifier(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
@@ -0,0 +1,50 @@
+package db2struct
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSampleJSONColumnsContext(t *testing.T) {
+	dsn := newTestSqliteDatabase(t)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE "user events" (id INTEGER PRIMARY KEY, payload JSON, note TEXT);
+INSERT INTO "user events" (payload, note) VALUES ('{"a": 1}', 'x'), (NULL, 'y'), ('{"b": true}', 'z'), ('{"c": 2}', NULL);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	introspector, err := NewIntrospector(DialectSqlite, ConnectionConfig{DSN: dsn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer introspector.Close()
+	table, err := introspector.DescribeTable("user events")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = introspector.(SamplingIntrospector).SampleJSONColumnsContext(context.Background(), table, 2)
+	Convey("Should sample non-null values of json columns", t, func() {
+		So(err, ShouldBeNil)
+		So(table.Column("payload").Samples, ShouldResemble, []string{`{"a": 1}`, `{"b": true}`})
+		So(table.Column("note").Samples, ShouldBeNil)
+	})
+
+	var _ SamplingIntrospector = &mysqlIntrospector{}
+	var _ SamplingIntrospector = &postgresIntrospector{}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	Convey("Should quote identifiers", t, func() {
+		So(quoteMysqlIdentifier("a`b"), ShouldEqual, "`a``b`")
+		So(quotePostgresIdentifier(`a"b`), ShouldEqual, `"a""b"`)
+	})
+}
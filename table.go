@@ -0,0 +1,373 @@
+package db2struct
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialects of the databases a Table can be described from
+const (
+	DialectMysql    = "mysql"
+	DialectPostgres = "postgres"
+	DialectSqlite   = "sqlite"
+)
+
+// Keys of a Column, as reported by the COLUMN_KEY of the mysql INFORMATION_SCHEMA
+const (
+	// KeyPrimary is the key of primary key columns
+	KeyPrimary = "PRI"
+	// KeyUnique is the key of columns with a single column unique index
+	KeyUnique = "UNI"
+	// KeyMultiple is the key of the first column of any other index
+	KeyMultiple = "MUL"
+)
+
+// Table describes a database table and its columns
+type Table struct {
+	// Name of the table
+	Name string
+	// Schema is the database (mysql) or schema (postgres) of the table, if known
+	Schema string
+	// Dialect is the database the table is described from, DialectMysql if empty
+	Dialect string
+	// Columns of the table in ordinal order
+	Columns []*Column
+	// Indexes of the table in name order, including the primary key
+	Indexes []*Index
+	// ForeignKeys of the table referencing other tables, in name order
+	ForeignKeys []*ForeignKey
+	// ReferencedBy holds the foreign keys of other tables, or of the table itself, referencing the table
+	ReferencedBy []*ForeignKey
+}
+
+// Index describes an index of a database table
+type Index struct {
+	// Name of the index, PRIMARY for the mysql primary key
+	Name string
+	// Columns of the index in index order
+	Columns []string
+	// Lengths are the prefix lengths of the columns in the order of Columns, 0 for columns indexed in full. Nil if no
+	// column is indexed by a prefix.
+	Lengths []int64
+	// Unique is set for unique indexes and the primary key
+	Unique bool
+	// Primary is set for the primary key
+	Primary bool
+}
+
+// Position returns the 1-based position of a column in the index, 0 if the index does not contain the column
+func (i *Index) Position(column string) int {
+	for position, name := range i.Columns {
+		if name == column {
+			return position + 1
+		}
+	}
+	return 0
+}
+
+// Length returns the prefix length of a column of the index, 0 if the column is indexed in full
+func (i *Index) Length(column string) int64 {
+	if position := i.Position(column); position > 0 && i.Lengths != nil {
+		return i.Lengths[position-1]
+	}
+	return 0
+}
+
+// addColumn appends a column indexed by a prefix of length, or in full if length is 0
+func (i *Index) addColumn(column string, length int64) {
+	if length > 0 && i.Lengths == nil {
+		i.Lengths = make([]int64, len(i.Columns))
+	}
+	i.Columns = append(i.Columns, column)
+	if i.Lengths != nil {
+		i.Lengths = append(i.Lengths, length)
+	}
+}
+
+// ForeignKey describes a foreign key constraint
+type ForeignKey struct {
+	// Name of the constraint
+	Name string
+	// Table holding the foreign key
+	Table string
+	// Columns of the foreign key in key order
+	Columns []string
+	// ReferencedTable is the table the foreign key references
+	ReferencedTable string
+	// ReferencedColumns are the referenced columns, in the order of Columns
+	ReferencedColumns []string
+	// OnDelete is the referential action on delete, such as CASCADE or NO ACTION
+	OnDelete string
+	// OnUpdate is the referential action on update, such as CASCADE or NO ACTION
+	OnUpdate string
+}
+
+// Column describes a column of a database table
+type Column struct {
+	// Name of the column
+	Name string
+	// DataType is the type of the column without length or attributes, such as varchar or int for mysql,
+	// the udt name such as int4 or _text for postgres and the declared type for sqlite
+	DataType string
+	// ColumnType is the full type of the column, such as varchar(255) or int(10) unsigned
+	ColumnType string
+	// Nullable is set if the column accepts NULL
+	Nullable bool
+	// Key is KeyPrimary, KeyUnique, KeyMultiple or empty
+	Key string
+	// Length is the maximum length of character and binary columns
+	Length int64
+	// Precision is the precision of numeric columns
+	Precision int64
+	// Scale is the scale of numeric columns
+	Scale int64
+	// Default is the default value expression of the column, nil if there is none
+	Default *string
+	// Extra holds additional attributes of the column in the format of the mysql EXTRA, such as auto_increment,
+	// on update CURRENT_TIMESTAMP or VIRTUAL GENERATED. Postgres identity columns are auto_increment.
+	Extra string
+	// GenerationExpression is the expression of generated columns, if known
+	GenerationExpression string
+	// Comment of the column
+	Comment string
+	// Samples are values of a json column, such as read by SampleJSONColumnsContext. The struct of the values of the
+	// column is inferred from them.
+	Samples []string
+}
+
+// Column returns the column with the given name, or nil if the table has none
+func (t *Table) Column(name string) *Column {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// ColumnIndexes returns the indexes containing a column in name order, including the primary key
+func (t *Table) ColumnIndexes(column string) []*Index {
+	var indexes []*Index
+	for _, index := range t.Indexes {
+		if index.Position(column) > 0 {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+// PrimaryKey returns the columns of the primary key in key order, from the primary index or, for tables without
+// indexes, the primary key columns in column order. Nil if the table has no primary key.
+func (t *Table) PrimaryKey() []string {
+	for _, index := range t.Indexes {
+		if index.Primary {
+			return index.Columns
+		}
+	}
+	var columns []string
+	for _, column := range t.Columns {
+		if column.Key == KeyPrimary {
+			columns = append(columns, column.Name)
+		}
+	}
+	return columns
+}
+
+// UniqueIndexes returns the unique indexes of the table in name order, including the primary key. Every unique
+// index identifies a single row, such as to look a row up by email.
+func (t *Table) UniqueIndexes() []*Index {
+	var indexes []*Index
+	for _, index := range t.Indexes {
+		if index.Unique {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+// addForeignKeys adds the foreign keys to the ForeignKeys of their table and the ReferencedBy of the referenced
+// table, foreign keys of tables which are not given are only added as references
+func addForeignKeys(tables []*Table, foreignKeys []*ForeignKey) {
+	tablesByName := make(map[string]*Table, len(tables))
+	for _, table := range tables {
+		tablesByName[table.Name] = table
+	}
+	for _, foreignKey := range foreignKeys {
+		if table := tablesByName[foreignKey.Table]; table != nil {
+			table.ForeignKeys = append(table.ForeignKeys, foreignKey)
+		}
+		if table := tablesByName[foreignKey.ReferencedTable]; table != nil {
+			table.ReferencedBy = append(table.ReferencedBy, foreignKey)
+		}
+	}
+}
+
+// dialect returns the dialect of the table, defaulting to mysql
+func (t *Table) dialect() string {
+	if t.Dialect == "" {
+		return DialectMysql
+	}
+	return t.Dialect
+}
+
+// columnMap returns the column details in the map of map format of GetColumnsFromMysqlTable, the column type is
+// added as column_type if it differs from the data type
+func (t *Table) columnMap() (map[string]map[string]string, []string) {
+	columnNamesSorted := []string{}
+	columnDataTypes := make(map[string]map[string]string)
+	for _, column := range t.Columns {
+		nullable := "NO"
+		if column.Nullable {
+			nullable = "YES"
+		}
+		columnDataTypes[column.Name] = map[string]string{"value": column.DataType, "nullable": nullable, "primary": column.Key, "comment": column.Comment}
+		if column.ColumnType != "" && column.ColumnType != column.DataType {
+			columnDataTypes[column.Name]["column_type"] = column.ColumnType
+		}
+		if t.Dialect != "" && t.Dialect != DialectMysql {
+			columnDataTypes[column.Name]["dialect"] = t.Dialect
+		}
+		columnNamesSorted = append(columnNamesSorted, column.Name)
+	}
+	return columnDataTypes, columnNamesSorted
+}
+
+// tableFromColumnMap returns the table described by a map of map in the format of GetColumnsFromMysqlTable
+func tableFromColumnMap(tableName string, columnTypes map[string]map[string]string, columnsSorted []string) *Table {
+	table := &Table{Name: tableName}
+	for _, name := range columnsSorted {
+		details := columnTypes[name]
+		columnType := details["column_type"]
+		if columnType == "" {
+			columnType = details["value"]
+		}
+		table.Columns = append(table.Columns, &Column{
+			Name:       name,
+			DataType:   details["value"],
+			ColumnType: columnType,
+			Nullable:   details["nullable"] == "YES",
+			Key:        details["primary"],
+			Comment:    details["comment"],
+		})
+		if dialect := details["dialect"]; dialect != "" {
+			table.Dialect = dialect
+		}
+	}
+	return table
+}
+
+// typeArguments returns the arguments of a column type, such as 10 and 2 for decimal(10,2) unsigned
+func typeArguments(columnType string) []string {
+	start := strings.Index(columnType, "(")
+	end := strings.LastIndex(columnType, ")")
+	if start < 0 || end < start {
+		return nil
+	}
+	var args []string
+	for _, arg := range strings.Split(columnType[start+1:end], ",") {
+		args = append(args, strings.TrimSpace(arg))
+	}
+	return args
+}
+
+// setTypeSizes sets the length, or precision and scale, of a column from the arguments of its column type
+func (c *Column) setTypeSizes(numeric bool) {
+	args := typeArguments(c.ColumnType)
+	if len(args) == 0 {
+		return
+	}
+	first, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return
+	}
+	if !numeric {
+		c.Length = first
+		return
+	}
+	c.Precision = first
+	if len(args) > 1 {
+		c.Scale, _ = strconv.ParseInt(args[1], 10, 64)
+	}
+}
+
+// autoIncrement reports whether the values of the column are generated by a mysql auto_increment or a postgres
+// sequence
+func (c *Column) autoIncrement() bool {
+	return strings.Contains(strings.ToLower(c.Extra), "auto_increment") ||
+		(c.Default != nil && strings.HasPrefix(*c.Default, "nextval("))
+}
+
+// generated reports whether the column is a virtual or stored generated column, whose value can not be written
+func (c *Column) generated() bool {
+	extra := strings.ToLower(c.Extra)
+	return c.GenerationExpression != "" || strings.Contains(extra, "virtual generated") ||
+		strings.Contains(extra, "stored generated")
+}
+
+// onUpdate returns the expression the column is set to when its row is updated, such as CURRENT_TIMESTAMP, or an
+// empty string
+func (c *Column) onUpdate() string {
+	extra := strings.ToLower(c.Extra)
+	i := strings.Index(extra, "on update ")
+	if i < 0 {
+		return ""
+	}
+	if fields := strings.Fields(c.Extra[i+len("on update "):]); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// isJSON reports whether the column is a json column
+func (c *Column) isJSON() bool {
+	dataType := strings.ToLower(c.DataType)
+	return dataType == "json" || dataType == "jsonb"
+}
+
+// EnumValues returns the allowed values of a mysql enum column parsed from its column type, such as
+// enum('new','done'), or nil for other columns
+func (c *Column) EnumValues() []string {
+	if !strings.EqualFold(c.DataType, "enum") || !strings.HasPrefix(strings.ToLower(c.ColumnType), "enum(") {
+		return nil
+	}
+	return quotedTypeValues(c.ColumnType)
+}
+
+// SetValues returns the allowed values of a mysql set column parsed from its column type, such as set('a','b'), or
+// nil for other columns
+func (c *Column) SetValues() []string {
+	if !strings.EqualFold(c.DataType, "set") || !strings.HasPrefix(strings.ToLower(c.ColumnType), "set(") {
+		return nil
+	}
+	return quotedTypeValues(c.ColumnType)
+}
+
+// quotedTypeValues returns the quoted values of the arguments of a column type, such as new and done for
+// enum('new','done'). Quotes are escaped by doubling them or with a backslash.
+func quotedTypeValues(columnType string) []string {
+	values := []string{}
+	var value strings.Builder
+	var quote byte
+	for i := strings.IndexByte(columnType, '(') + 1; i < len(columnType); i++ {
+		c := columnType[i]
+		switch {
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case quote == 0:
+			// separators and the closing parenthesis
+		case c == quote && i+1 < len(columnType) && columnType[i+1] == quote:
+			value.WriteByte(c)
+			i++
+		case c == quote:
+			values = append(values, value.String())
+			value.Reset()
+			quote = 0
+		case c == '\\' && i+1 < len(columnType):
+			value.WriteByte(columnType[i+1])
+			i++
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return values
+}
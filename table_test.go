@@ -0,0 +1,134 @@
+package db2struct
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTableColumnMap(t *testing.T) {
+	table := &Table{Name: "users", Dialect: DialectPostgres, Columns: []*Column{
+		{Name: "id", DataType: "int4", Key: KeyPrimary},
+		{Name: "name", DataType: "text", Nullable: true, Comment: "full name"},
+	}}
+	columnMap, columnsSorted := table.columnMap()
+	Convey("Should convert a table to a map of map", t, func() {
+		So(columnsSorted, ShouldResemble, []string{"id", "name"})
+		So(columnMap["id"], ShouldResemble, map[string]string{"value": "int4", "nullable": "NO", "primary": "PRI", "comment": "", "dialect": "postgres"})
+		So(columnMap["name"], ShouldResemble, map[string]string{"value": "text", "nullable": "YES", "primary": "", "comment": "full name", "dialect": "postgres"})
+	})
+
+	converted := tableFromColumnMap("users", columnMap, columnsSorted)
+	Convey("Should convert a map of map back to a table", t, func() {
+		So(converted.Name, ShouldEqual, "users")
+		So(converted.Dialect, ShouldEqual, DialectPostgres)
+		So(converted.Columns, ShouldHaveLength, 2)
+		So(converted.Column("name").Nullable, ShouldBeTrue)
+		So(converted.Column("name").Comment, ShouldEqual, "full name")
+		So(converted.Column("id").Key, ShouldEqual, KeyPrimary)
+		So(converted.Column("missing"), ShouldBeNil)
+	})
+}
+
+func TestColumnTypeSizes(t *testing.T) {
+	decimal := &Column{ColumnType: "decimal(10, 2) unsigned"}
+	decimal.setTypeSizes(true)
+	varchar := &Column{ColumnType: "varchar(255)"}
+	varchar.setTypeSizes(false)
+	text := &Column{ColumnType: "text"}
+	text.setTypeSizes(false)
+	Convey("Should read the sizes of a column from its type", t, func() {
+		So(decimal.Precision, ShouldEqual, 10)
+		So(decimal.Scale, ShouldEqual, 2)
+		So(varchar.Length, ShouldEqual, 255)
+		So(text.Length, ShouldEqual, 0)
+	})
+}
+
+func TestColumnEnumValues(t *testing.T) {
+	Convey("Should parse the values of an enum column", t, func() {
+		column := &Column{DataType: "enum", ColumnType: `enum('new','it''s done','a\\b','',"x,y")`}
+		So(column.EnumValues(), ShouldResemble, []string{"new", "it's done", "a\\b", "", "x,y"})
+	})
+	Convey("Should parse the values of a set column", t, func() {
+		column := &Column{DataType: "SET", ColumnType: "SET('a','b')"}
+		So(column.SetValues(), ShouldResemble, []string{"a", "b"})
+		So(column.EnumValues(), ShouldBeNil)
+	})
+	Convey("Should not return values for other columns", t, func() {
+		So((&Column{DataType: "varchar", ColumnType: "varchar(255)"}).EnumValues(), ShouldBeNil)
+		So((&Column{DataType: "varchar", ColumnType: "varchar(255)"}).SetValues(), ShouldBeNil)
+		So((&Column{DataType: "enum", ColumnType: "mood"}).EnumValues(), ShouldBeNil)
+	})
+}
+
+func TestColumnGenerated(t *testing.T) {
+	Convey("Should detect generated columns by their expression or extra", t, func() {
+		So((&Column{GenerationExpression: "`price` * `qty`"}).generated(), ShouldBeTrue)
+		So((&Column{Extra: "STORED GENERATED"}).generated(), ShouldBeTrue)
+		So((&Column{Extra: "VIRTUAL GENERATED"}).generated(), ShouldBeTrue)
+		So((&Column{Extra: "DEFAULT_GENERATED"}).generated(), ShouldBeFalse)
+	})
+	Convey("Should return the on update expression of a column", t, func() {
+		So((&Column{Extra: "DEFAULT_GENERATED on update CURRENT_TIMESTAMP(3)"}).onUpdate(), ShouldEqual, "CURRENT_TIMESTAMP(3)")
+		So((&Column{Extra: "on update current_timestamp"}).onUpdate(), ShouldEqual, "current_timestamp")
+		So((&Column{Extra: "auto_increment"}).onUpdate(), ShouldBeEmpty)
+	})
+}
+
+func TestTableIndexes(t *testing.T) {
+	primary := &Index{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true}
+	email := &Index{Name: "users_email", Columns: []string{"email"}, Lengths: []int64{191}, Unique: true}
+	orgName := &Index{Name: "users_org_name", Columns: []string{"org_id", "name"}}
+	table := &Table{Name: "users", Indexes: []*Index{primary, email, orgName}}
+	Convey("Should look up the indexes of a column and the unique indexes", t, func() {
+		So(table.ColumnIndexes("id"), ShouldResemble, []*Index{primary})
+		So(table.ColumnIndexes("name"), ShouldResemble, []*Index{orgName})
+		So(table.ColumnIndexes("comment"), ShouldBeEmpty)
+		So(table.UniqueIndexes(), ShouldResemble, []*Index{primary, email})
+	})
+	Convey("Should report the position and prefix length of index columns", t, func() {
+		So(orgName.Position("name"), ShouldEqual, 2)
+		So(orgName.Position("email"), ShouldEqual, 0)
+		So(orgName.Length("name"), ShouldEqual, 0)
+		So(email.Length("email"), ShouldEqual, 191)
+	})
+
+	Convey("Should return the primary key columns in key order", t, func() {
+		So(table.PrimaryKey(), ShouldResemble, []string{"id"})
+		So((&Table{Columns: []*Column{{Name: "b", Key: KeyPrimary}, {Name: "c"}, {Name: "a", Key: KeyPrimary}}}).PrimaryKey(),
+			ShouldResemble, []string{"b", "a"})
+		So((&Table{Columns: []*Column{{Name: "a"}}}).PrimaryKey(), ShouldBeNil)
+	})
+
+	index := &Index{}
+	index.addColumn("name", 0)
+	index.addColumn("email", 10)
+	index.addColumn("id", 0)
+	Convey("Should only track prefix lengths of indexes with prefixed columns", t, func() {
+		So(index.Columns, ShouldResemble, []string{"name", "email", "id"})
+		So(index.Lengths, ShouldResemble, []int64{0, 10, 0})
+	})
+}
+
+func TestGenerateFromTable(t *testing.T) {
+	expectedStruct :=
+		`package test
+
+import "database/sql"
+
+type testStruct struct {
+	ID    int32          ` + "`json:\"id\"`" + `
+	Email sql.NullString ` + "`json:\"email\"`" + ` //login
+}
+`
+	table := &Table{Name: "users", Columns: []*Column{
+		{Name: "id", DataType: "int", ColumnType: "int(11)", Key: KeyPrimary},
+		{Name: "email", DataType: "varchar", ColumnType: "varchar(255)", Nullable: true, Comment: "login"},
+	}}
+	bytes, err := GenerateFromTable(table, "testStruct", "test", true, false, false)
+	Convey("Should be able to generate a struct from a table", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+}
@@ -0,0 +1,240 @@
+package db2struct
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TagField describes the struct field of a column a tag is generated for
+type TagField struct {
+	// Table and Column the field is generated for
+	Table  *Table
+	Column *Column
+	// Name and Type of the field
+	Name string
+	Type string
+	// OmitEmpty is set for nullable columns if the OmitEmpty option is set
+	OmitEmpty bool
+}
+
+// TagEmitter returns the value of a struct tag of a field, such as "id,pk" for bun:"id,pk". No tag is added to the
+// field if the value is empty.
+type TagEmitter func(field TagField) string
+
+var (
+	tagEmittersMu sync.RWMutex
+	tagEmitters   = map[string]TagEmitter{
+		TagJSON:         encodingTagEmitter,
+		TagGorm:         gormTagEmitter,
+		TagDB:           dbTagEmitter,
+		TagBun:          bunTagEmitter,
+		TagXorm:         xormTagEmitter,
+		TagPg:           pgTagEmitter,
+		TagSQL:          pgTagEmitter,
+		TagYAML:         encodingTagEmitter,
+		TagTOML:         encodingTagEmitter,
+		TagXML:          encodingTagEmitter,
+		TagBSON:         encodingTagEmitter,
+		TagMsgpack:      encodingTagEmitter,
+		TagMapstructure: encodingTagEmitter,
+	}
+)
+
+// RegisterTagEmitter makes a struct tag available by the given key, such as db for db:"column". If
+// RegisterTagEmitter is called twice with the same key or if emitter is nil, it panics.
+func RegisterTagEmitter(key string, emitter TagEmitter) {
+	tagEmittersMu.Lock()
+	defer tagEmittersMu.Unlock()
+	if emitter == nil {
+		panic("db2struct: RegisterTagEmitter emitter is nil")
+	}
+	if _, dup := tagEmitters[key]; dup {
+		panic("db2struct: RegisterTagEmitter called twice for tag " + key)
+	}
+	tagEmitters[key] = emitter
+}
+
+// TagEmitters returns the keys of the registered tags in name order
+func TagEmitters() []string {
+	tagEmittersMu.RLock()
+	defer tagEmittersMu.RUnlock()
+	tags := make([]string, 0, len(tagEmitters))
+	for tag := range tagEmitters {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// tagEmitter returns the emitter registered for the tag
+func tagEmitter(tag string) (TagEmitter, bool) {
+	tagEmittersMu.RLock()
+	defer tagEmittersMu.RUnlock()
+	emitter, ok := tagEmitters[tag]
+	return emitter, ok
+}
+
+// encodingTagEmitter emits the column name, with omitempty if requested, for json, yaml, toml, xml, bson, msgpack
+// and mapstructure
+func encodingTagEmitter(field TagField) string {
+	if field.OmitEmpty {
+		return field.Column.Name + ",omitempty"
+	}
+	return field.Column.Name
+}
+
+// gormTagEmitter emits GORM v2 settings, so that AutoMigrate reproduces the table: the column name and type, size,
+// primaryKey, autoIncrement, disabled for the integer columns of composite keys, not null, default, read only (->) for
+// generated columns, autoUpdateTime for columns updated to the current timestamp, the index or uniqueIndex of every
+// index of the column, with the priority of the column in composite indexes and its prefix length, and the comment
+func gormTagEmitter(field TagField) string {
+	column := field.Column
+	settings := []string{"column:" + column.Name}
+	columnType := gormColumnType(field)
+	if columnType {
+		settings = append(settings, "type:"+escapeGormSetting(column.ColumnType))
+	}
+	if column.Length > 0 && (!columnType || strings.Contains(column.ColumnType, "(")) {
+		settings = append(settings, "size:"+strconv.FormatInt(column.Length, 10))
+	}
+	if column.Key == KeyPrimary {
+		settings = append(settings, "primaryKey")
+	}
+	autoIncrement := column.autoIncrement()
+	if autoIncrement {
+		settings = append(settings, "autoIncrement")
+	} else if column.Key == KeyPrimary && field.Table != nil && len(field.Table.PrimaryKey()) > 1 && isIntegerType(field.Type) {
+		// GORM makes an integer id column of a composite key auto increment unless disabled
+		settings = append(settings, "autoIncrement:false")
+	}
+	if !column.Nullable && column.Key != KeyPrimary {
+		settings = append(settings, "not null")
+	}
+	if column.Default != nil && !autoIncrement && !strings.EqualFold(*column.Default, "NULL") {
+		settings = append(settings, "default:"+escapeGormSetting(*column.Default))
+	}
+	if column.generated() {
+		settings = append(settings, "->")
+	}
+	if isCurrentTimestamp(column.onUpdate()) {
+		settings = append(settings, "autoUpdateTime")
+	}
+	if field.Table != nil {
+		for _, index := range field.Table.ColumnIndexes(column.Name) {
+			if index.Primary {
+				continue
+			}
+			setting := "index:" + escapeGormSetting(index.Name)
+			if index.Unique {
+				setting = "uniqueIndex:" + escapeGormSetting(index.Name)
+			}
+			if len(index.Columns) > 1 {
+				setting += ",priority:" + strconv.Itoa(index.Position(column.Name))
+			}
+			if length := index.Length(column.Name); length > 0 {
+				setting += ",length:" + strconv.FormatInt(length, 10)
+			}
+			settings = append(settings, setting)
+		}
+	}
+	if column.Comment != "" {
+		settings = append(settings, "comment:"+escapeGormSetting(column.Comment))
+	}
+	return strings.Join(settings, ";")
+}
+
+// gormColumnType reports whether the column type of a field can be used as the GORM type. Mysql types taking
+// parameters, such as varchar or decimal, are only used with their parameters, as the bare data type of the Generate
+// maps is not a valid type or loses the length, precision and values of the column.
+func gormColumnType(field TagField) bool {
+	column := field.Column
+	if column.ColumnType == "" || strings.Contains(column.ColumnType, "(") {
+		return column.ColumnType != ""
+	}
+	if field.Table != nil && field.Table.dialect() != DialectMysql {
+		return true
+	}
+	switch strings.ToLower(column.DataType) {
+	case "char", "varchar", "nchar", "nvarchar", "binary", "varbinary", "decimal", "numeric", "enum", "set", "bit":
+		return false
+	}
+	return true
+}
+
+// isCurrentTimestamp reports whether an expression is the current timestamp, such as CURRENT_TIMESTAMP(6) or now()
+func isCurrentTimestamp(expression string) bool {
+	expression = strings.ToLower(expression)
+	return strings.HasPrefix(expression, "current_timestamp") || strings.HasPrefix(expression, "localtimestamp") ||
+		strings.HasPrefix(expression, "now(")
+}
+
+// isIntegerType reports whether a go type is a signed or unsigned integer type
+func isIntegerType(goType string) bool {
+	goType = strings.TrimPrefix(goType, "u")
+	return goType == "int" || goType == "int8" || goType == "int16" || goType == "int32" || goType == "int64"
+}
+
+// escapeGormSetting escapes the separators of GORM settings in a value
+func escapeGormSetting(value string) string {
+	return strings.Replace(value, ";", "\\;", -1)
+}
+
+// dbTagEmitter emits the column name for sqlx and scany, which have no other options
+func dbTagEmitter(field TagField) string {
+	return field.Column.Name
+}
+
+// bunTagEmitter emits the column name with pk, autoincrement, notnull and scanonly for generated columns, which bun
+// only reads
+func bunTagEmitter(field TagField) string {
+	options := []string{field.Column.Name}
+	if field.Column.Key == KeyPrimary {
+		options = append(options, "pk")
+		if field.Column.autoIncrement() {
+			options = append(options, "autoincrement")
+		}
+	} else if !field.Column.Nullable {
+		options = append(options, "notnull")
+	}
+	if field.Column.generated() {
+		options = append(options, "scanonly")
+	}
+	return strings.Join(options, ",")
+}
+
+// xormTagEmitter emits the quoted column name with pk, autoincr, read only (<-) for generated columns, updated for
+// columns updated to the current timestamp and notnull or null
+func xormTagEmitter(field TagField) string {
+	options := []string{"'" + field.Column.Name + "'"}
+	if field.Column.Key == KeyPrimary {
+		options = append(options, "pk")
+		if field.Column.autoIncrement() {
+			options = append(options, "autoincr")
+		}
+	}
+	if field.Column.generated() {
+		options = append(options, "<-")
+	}
+	if isCurrentTimestamp(field.Column.onUpdate()) {
+		options = append(options, "updated")
+	}
+	if field.Column.Nullable {
+		options = append(options, "null")
+	} else {
+		options = append(options, "notnull")
+	}
+	return strings.Join(options, " ")
+}
+
+// pgTagEmitter emits the column name with pk and notnull for go-pg, whose older versions read the sql tag
+func pgTagEmitter(field TagField) string {
+	if field.Column.Key == KeyPrimary {
+		return field.Column.Name + ",pk"
+	}
+	if !field.Column.Nullable {
+		return field.Column.Name + ",notnull"
+	}
+	return field.Column.Name
+}
@@ -0,0 +1,140 @@
+package db2struct
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTagEmitters(t *testing.T) {
+	table := &Table{Name: "users", Columns: []*Column{
+		{Name: "id", DataType: "int", ColumnType: "int(11)", Key: KeyPrimary, Extra: "auto_increment"},
+		{Name: "email", DataType: "varchar", ColumnType: "varchar(255)"},
+		{Name: "name", DataType: "varchar", ColumnType: "varchar(255)", Nullable: true},
+	}}
+	tags := []string{TagDB, TagBun, TagXorm, TagPg, TagSQL, TagJSON, TagYAML, TagTOML, TagXML, TagBSON, TagMsgpack, TagMapstructure}
+	expected := map[string][]string{
+		TagDB:           {"id", "email", "name"},
+		TagBun:          {"id,pk,autoincrement", "email,notnull", "name"},
+		TagXorm:         {"'id' pk autoincr notnull", "'email' notnull", "'name' null"},
+		TagPg:           {"id,pk", "email,notnull", "name"},
+		TagSQL:          {"id,pk", "email,notnull", "name"},
+		TagJSON:         {"id", "email", "name,omitempty"},
+		TagYAML:         {"id", "email", "name,omitempty"},
+		TagTOML:         {"id", "email", "name,omitempty"},
+		TagXML:          {"id", "email", "name,omitempty"},
+		TagBSON:         {"id", "email", "name,omitempty"},
+		TagMsgpack:      {"id", "email", "name,omitempty"},
+		TagMapstructure: {"id", "email", "name,omitempty"},
+	}
+	Convey("Should emit the tags of every built in mapper", t, func() {
+		for _, tag := range tags {
+			emitter, ok := tagEmitter(tag)
+			So(ok, ShouldBeTrue)
+			for i, column := range table.Columns {
+				field := TagField{Table: table, Column: column, OmitEmpty: column.Nullable}
+				So(emitter(field), ShouldEqual, expected[tag][i])
+			}
+		}
+	})
+
+	Convey("Should list the registered tags", t, func() {
+		So(TagEmitters(), ShouldContain, TagDB)
+		So(TagEmitters(), ShouldContain, TagMapstructure)
+	})
+}
+
+func TestRegisterTagEmitter(t *testing.T) {
+	RegisterTagEmitter("test", func(field TagField) string {
+		if field.Column.Key == KeyPrimary {
+			return ""
+		}
+		return field.Name + ":" + field.Type
+	})
+	Convey("Should panic when registering a tag twice or a nil emitter", t, func() {
+		So(func() { RegisterTagEmitter("test", dbTagEmitter) }, ShouldPanic)
+		So(func() { RegisterTagEmitter("other", nil) }, ShouldPanic)
+	})
+
+	expectedStruct :=
+		`package test
+
+import "database/sql"
+
+type Users struct {
+	ID   int32          ` + "`db:\"id\"`" + `
+	Name sql.NullString ` + "`db:\"name\" test:\"Name:sql.NullString\"`" + `
+}
+`
+	table := &Table{Name: "users", Columns: []*Column{
+		{Name: "id", DataType: "int", ColumnType: "int(11)", Key: KeyPrimary},
+		{Name: "name", DataType: "varchar", ColumnType: "varchar(255)", Nullable: true},
+	}}
+	bytes, err := GenerateStruct(table, "", GenerateOptions{PackageName: "test", Tags: []string{"db", "test"}})
+	Convey("Should be able to generate registered tags and omit empty ones", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+}
+
+func TestGormTagEmitter(t *testing.T) {
+	defaultName := "anon"
+	nextval := "nextval('users_id_seq'::regclass)"
+	table := &Table{Name: "users", Dialect: DialectPostgres, Columns: []*Column{
+		{Name: "id", DataType: "int4", ColumnType: "integer", Key: KeyPrimary, Default: &nextval},
+		{Name: "email", DataType: "varchar", ColumnType: "varchar(255)", Length: 255, Key: KeyUnique},
+		{Name: "org_id", DataType: "int4", ColumnType: "integer", Key: KeyMultiple},
+		{Name: "name", DataType: "varchar", ColumnType: "varchar(64)", Length: 64, Nullable: true, Default: &defaultName, Comment: "display; name"},
+	}, Indexes: []*Index{
+		{Name: "users_email", Columns: []string{"email"}, Unique: true},
+		{Name: "users_org_name", Columns: []string{"org_id", "name"}},
+		{Name: "users_pkey", Columns: []string{"id"}, Unique: true, Primary: true},
+	}}
+	expected := []string{
+		"column:id;type:integer;primaryKey;autoIncrement",
+		"column:email;type:varchar(255);size:255;not null;uniqueIndex:users_email",
+		"column:org_id;type:integer;not null;index:users_org_name,priority:1",
+		"column:name;type:varchar(64);size:64;default:anon;index:users_org_name,priority:2;comment:display\\; name",
+	}
+	Convey("Should emit GORM v2 settings", t, func() {
+		for i, column := range table.Columns {
+			So(gormTagEmitter(TagField{Table: table, Column: column}), ShouldEqual, expected[i])
+		}
+	})
+
+	bytes, err := GenerateStruct(table, "", GenerateOptions{PackageName: "test", Tags: []string{TagGorm}})
+	Convey("Should escape the quotes of tags", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldContainSubstring, "`gorm:\"column:name;type:varchar(64);size:64;default:anon;index:users_org_name,priority:2;comment:display\\\\; name\"`")
+	})
+	table.Indexes[0] = &Index{Name: "users_email", Columns: []string{"email"}, Lengths: []int64{191}, Unique: true}
+	table.Columns[2].Key = KeyPrimary
+	table.Indexes[2].Columns = []string{"id", "org_id"}
+	Convey("Should disable auto increment of integer columns of composite primary keys", t, func() {
+		So(gormTagEmitter(TagField{Table: table, Column: table.Columns[2], Type: "int32"}), ShouldEqual,
+			"column:org_id;type:integer;primaryKey;autoIncrement:false;index:users_org_name,priority:1")
+		So(gormTagEmitter(TagField{Table: table, Column: table.Columns[0], Type: "int32"}), ShouldEqual,
+			"column:id;type:integer;primaryKey;autoIncrement")
+	})
+	Convey("Should only emit mysql types taking parameters with their parameters", t, func() {
+		So(gormTagEmitter(TagField{Column: &Column{Name: "name", DataType: "varchar", ColumnType: "varchar", Length: 64}}), ShouldEqual,
+			"column:name;size:64;not null")
+		So(gormTagEmitter(TagField{Column: &Column{Name: "price", DataType: "decimal", ColumnType: "decimal", Nullable: true}}), ShouldEqual,
+			"column:price")
+		So(gormTagEmitter(TagField{Column: &Column{Name: "count", DataType: "int", ColumnType: "int"}}), ShouldEqual,
+			"column:count;type:int;not null")
+	})
+	Convey("Should make generated columns read only and update on update timestamps", t, func() {
+		total := &Column{Name: "total", DataType: "int4", ColumnType: "integer", Nullable: true, Extra: "STORED GENERATED", GenerationExpression: "(price * qty)"}
+		So(gormTagEmitter(TagField{Column: total}), ShouldEqual, "column:total;type:integer;->")
+		So(xormTagEmitter(TagField{Column: total}), ShouldEqual, "'total' <- null")
+		So(bunTagEmitter(TagField{Column: total}), ShouldEqual, "total,scanonly")
+		updatedAt := &Column{Name: "updated_at", DataType: "timestamp", ColumnType: "timestamp", Extra: "on update CURRENT_TIMESTAMP"}
+		So(gormTagEmitter(TagField{Column: updatedAt}), ShouldEqual, "column:updated_at;type:timestamp;not null;autoUpdateTime")
+		So(xormTagEmitter(TagField{Column: updatedAt}), ShouldEqual, "'updated_at' updated notnull")
+	})
+	Convey("Should emit the prefix length of index columns", t, func() {
+		So(gormTagEmitter(TagField{Table: table, Column: table.Columns[1]}), ShouldEqual,
+			"column:email;type:varchar(255);size:255;not null;uniqueIndex:users_email,length:191")
+	})
+}
@@ -0,0 +1,190 @@
+package db2struct
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TypeMapping is the go type a column is generated with instead of the type of the dialect
+type TypeMapping struct {
+	// Type is the go type of the field, such as decimal.Decimal
+	Type string `json:"type"`
+	// NullableType is the go type of the field of nullable columns. If empty, nullable columns get a pointer to Type,
+	// or Type wrapped by the NullTypesGeneric type.
+	NullableType string `json:"nullable_type,omitempty"`
+	// Import is the import path of the package of the type, such as github.com/shopspring/decimal
+	Import string `json:"import,omitempty"`
+}
+
+// TypeMap maps columns to go types. Keys are exact table.column names, full column types such as tinyint(1) or
+// binary(16), or data types such as decimal. Table and column names take precedence over column types, which take
+// precedence over data types. Types are matched case insensitively, column and data type keys are lower case as
+// returned by ParseTypeMapping and LoadTypeMap.
+type TypeMap map[string]TypeMapping
+
+// majorVersion matches the major version suffix of an import path, such as v2 or null.v4
+var majorVersion = regexp.MustCompile(`^(.*)\.v[0-9]+$|^v[0-9]+$`)
+
+// LoadTypeMap Reads a type map from a JSON file of the form
+//
+//	{
+//		"decimal": {"type": "decimal.Decimal", "nullable_type": "decimal.NullDecimal", "import": "github.com/shopspring/decimal"},
+//		"binary(16)": {"type": "uuid.UUID", "import": "github.com/google/uuid"},
+//		"users.settings": {"type": "json.RawMessage", "import": "encoding/json"}
+//	}
+func LoadTypeMap(file string) (TypeMap, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	mappings := TypeMap{}
+	if err = json.Unmarshal(content, &mappings); err != nil {
+		return nil, fmt.Errorf("error reading type map %s: %s", file, err)
+	}
+	typeMap := make(TypeMap, len(mappings))
+	for key, mapping := range mappings {
+		if mapping.Type == "" {
+			return nil, fmt.Errorf("error reading type map %s: no type for %s", file, key)
+		}
+		typeMap[typeMapKey(key)] = mapping
+	}
+	return typeMap, nil
+}
+
+// ParseTypeMapping Parses a type mapping of the form key=type, where the type may be qualified by its import path
+//
+// For example decimal=github.com/shopspring/decimal.Decimal maps decimal columns to decimal.Decimal and imports
+// github.com/shopspring/decimal, json=encoding/json.RawMessage maps json columns to json.RawMessage and
+// users.id=uint64 maps the id column of the users table to uint64.
+func ParseTypeMapping(mapping string) (string, TypeMapping, error) {
+	parts := strings.SplitN(mapping, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return "", TypeMapping{}, fmt.Errorf("invalid type mapping %q, expected key=type", mapping)
+	}
+	key, goType := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	return typeMapKey(key), parseGoType(goType), nil
+}
+
+// typeMapKey returns the key of a type map entry, column and data types are lower cased so that they match case
+// insensitively, table.column names are kept as they are
+func typeMapKey(key string) string {
+	if strings.Contains(key, ".") {
+		return key
+	}
+	return strings.ToLower(key)
+}
+
+// parseGoType returns the mapping of a go type which may be qualified by its import path, such as
+// github.com/shopspring/decimal.Decimal
+func parseGoType(goType string) TypeMapping {
+	// an import path is everything before the last dot of the type, if it contains a slash
+	slash := strings.LastIndex(goType, "/")
+	dot := strings.LastIndex(goType, ".")
+	if slash < 0 || dot < slash {
+		return TypeMapping{Type: goType}
+	}
+
+	importPath, typeName := goType[:dot], goType[dot+1:]
+	prefix := ""
+	for strings.HasPrefix(importPath, "*") || strings.HasPrefix(importPath, "[]") {
+		if importPath[0] == '*' {
+			prefix, importPath = prefix+"*", importPath[1:]
+		} else {
+			prefix, importPath = prefix+"[]", importPath[2:]
+		}
+	}
+	return TypeMapping{Type: prefix + importPackageName(importPath) + "." + typeName, Import: importPath}
+}
+
+// importPackageName returns the conventional package name of an import path, without major version suffixes
+func importPackageName(importPath string) string {
+	name := path.Base(importPath)
+	if match := majorVersion.FindStringSubmatch(name); match != nil {
+		if match[1] != "" {
+			return match[1]
+		}
+		return path.Base(path.Dir(importPath))
+	}
+	return name
+}
+
+// lookup returns the mapping of a column of a table, if there is one
+func (m TypeMap) lookup(table *Table, column *Column) (TypeMapping, bool) {
+	if mapping, ok := m[table.Name+"."+column.Name]; ok {
+		return mapping, true
+	}
+	for _, key := range []string{column.ColumnType, strings.ToLower(column.ColumnType), column.DataType, strings.ToLower(column.DataType)} {
+		if mapping, ok := m[key]; ok && key != "" {
+			return mapping, true
+		}
+	}
+	return TypeMapping{}, false
+}
+
+// goType returns the go type of a column with the mapping
+func (m TypeMapping) goType(nullable bool) string {
+	if nullable && m.NullableType != "" {
+		return m.NullableType
+	}
+	return m.Type
+}
+
+// typeImports are the import paths of the packages of the generated types
+var typeImports = map[string]string{
+	"sql":  "database/sql",
+	"time": "time",
+	"null": "gopkg.in/guregu/null.v4",
+	"pq":   "github.com/lib/pq",
+}
+
+// qualifiedIdentifier matches the package names of the qualified identifiers of a type, such as sql and time in
+// sql.Null[time.Time]
+var qualifiedIdentifier = regexp.MustCompile(`\b([a-z][A-Za-z0-9_]*)\.[A-Z]`)
+
+// addTypeImports adds the import paths of the packages of a generated type to imports, such as database/sql for
+// sql.NullString
+func addTypeImports(goType string, imports map[string]bool) {
+	for _, match := range qualifiedIdentifier.FindAllStringSubmatch(goType, -1) {
+		if importPath, ok := typeImports[match[1]]; ok {
+			imports[importPath] = true
+		}
+	}
+}
+
+// importBlock returns the import declaration of the import paths sorted the way goimports does, standard library
+// packages first and other packages in a second group
+func importBlock(imports map[string]bool) string {
+	if len(imports) == 0 {
+		return ""
+	}
+	var std, other []string
+	for importPath := range imports {
+		if strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".") {
+			other = append(other, importPath)
+		} else {
+			std = append(std, importPath)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	if len(std)+len(other) == 1 {
+		return "\nimport \"" + append(std, other...)[0] + "\"\n"
+	}
+
+	src := "\nimport (\n"
+	for _, importPath := range std {
+		src += "\t\"" + importPath + "\"\n"
+	}
+	if len(std) > 0 && len(other) > 0 {
+		src += "\n"
+	}
+	for _, importPath := range other {
+		src += "\t\"" + importPath + "\"\n"
+	}
+	return src + ")\n"
+}
@@ -0,0 +1,172 @@
+package db2struct
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseTypeMapping(t *testing.T) {
+	key, mapping, err := ParseTypeMapping("decimal=github.com/shopspring/decimal.Decimal")
+	Convey("Should parse a type qualified by its import path", t, func() {
+		So(err, ShouldBeNil)
+		So(key, ShouldEqual, "decimal")
+		So(mapping, ShouldResemble, TypeMapping{Type: "decimal.Decimal", Import: "github.com/shopspring/decimal"})
+	})
+
+	_, mapping, err = ParseTypeMapping("tinyint(1) = gopkg.in/guregu/null.v4.Bool")
+	Convey("Should strip the version of gopkg.in packages", t, func() {
+		So(err, ShouldBeNil)
+		So(mapping, ShouldResemble, TypeMapping{Type: "null.Bool", Import: "gopkg.in/guregu/null.v4"})
+	})
+
+	_, mapping, err = ParseTypeMapping("point=*github.com/twpayne/go-geom/v2.Point")
+	Convey("Should strip major version directories and keep pointers", t, func() {
+		So(err, ShouldBeNil)
+		So(mapping, ShouldResemble, TypeMapping{Type: "*go-geom.Point", Import: "github.com/twpayne/go-geom/v2"})
+	})
+
+	_, mapping, err = ParseTypeMapping("users.id=uint64")
+	Convey("Should parse a type without an import path", t, func() {
+		So(err, ShouldBeNil)
+		So(mapping, ShouldResemble, TypeMapping{Type: "uint64"})
+	})
+
+	key, mapping, err = ParseTypeMapping("DECIMAL=github.com/shopspring/decimal.Decimal")
+	Convey("Should match type keys case insensitively and table.column keys exactly", t, func() {
+		So(err, ShouldBeNil)
+		So(key, ShouldEqual, "decimal")
+		table := &Table{Name: "Orders", Columns: []*Column{{Name: "Total", DataType: "decimal", ColumnType: "decimal(10,2)"}}}
+		_, ok := TypeMap{key: mapping}.lookup(table, table.Columns[0])
+		So(ok, ShouldBeTrue)
+		key, _, _ = ParseTypeMapping("Orders.Total=uint64")
+		So(key, ShouldEqual, "Orders.Total")
+	})
+
+	_, _, err = ParseTypeMapping("decimal")
+	Convey("Should get an error for a mapping without a type", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestLoadTypeMap(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "types.json")
+	if err := ioutil.WriteFile(file, []byte(`{"DECIMAL": {"type": "decimal.Decimal", "nullable_type": "decimal.NullDecimal", "import": "github.com/shopspring/decimal"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	typeMap, err := LoadTypeMap(file)
+	Convey("Should read a type map from a JSON file with lower case type keys", t, func() {
+		So(err, ShouldBeNil)
+		So(typeMap["decimal"].NullableType, ShouldEqual, "decimal.NullDecimal")
+	})
+
+	if err = ioutil.WriteFile(file, []byte(`{"decimal": {"import": "github.com/shopspring/decimal"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadTypeMap(file)
+	Convey("Should get an error for a mapping without a type", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestTypeMapGenerate(t *testing.T) {
+	expectedStruct :=
+		`package test
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type Users struct {
+	ID       uuid.UUID
+	Active   bool
+	Balance  decimal.NullDecimal
+	Price    decimal.Decimal
+	Settings json.RawMessage
+	Score    float64
+}
+`
+	table := &Table{Name: "users", Columns: []*Column{
+		{Name: "id", DataType: "binary", ColumnType: "binary(16)"},
+		{Name: "active", DataType: "tinyint", ColumnType: "tinyint(1)"},
+		{Name: "balance", DataType: "decimal", ColumnType: "decimal(10,2)", Nullable: true},
+		{Name: "price", DataType: "DECIMAL", ColumnType: "DECIMAL(10,2)"},
+		{Name: "settings", DataType: "text", ColumnType: "text"},
+		{Name: "score", DataType: "decimal", ColumnType: "decimal(10,2)"},
+	}}
+	bytes, err := GenerateStruct(table, "", GenerateOptions{
+		PackageName: "test",
+		TypeMap: TypeMap{
+			"decimal":        {Type: "decimal.Decimal", NullableType: "decimal.NullDecimal", Import: "github.com/shopspring/decimal"},
+			"binary(16)":     {Type: "uuid.UUID", Import: "github.com/google/uuid"},
+			"users.settings": {Type: "json.RawMessage", Import: "encoding/json"},
+			"users.score":    {Type: "float64"},
+		},
+	})
+	Convey("Should be able to generate the mapped types and their imports", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+}
+
+func TestTypeMapNullable(t *testing.T) {
+	table := &Table{Name: "orders", Columns: []*Column{
+		{Name: "discount", DataType: "decimal", ColumnType: "decimal(10,2)", Nullable: true},
+	}}
+	typeMap := TypeMap{"decimal": {Type: "decimal.Decimal", Import: "github.com/shopspring/decimal"}}
+	for _, nullTypes := range []string{NullTypesSQL, NullTypesGuregu, NullTypesPointer} {
+		bytes, err := GenerateStruct(table, "", GenerateOptions{PackageName: "test", NullTypes: nullTypes, TypeMap: typeMap})
+		Convey("Should use a pointer for nullable columns mapped without a nullable type with "+nullTypes+" null types", t, func() {
+			So(err, ShouldBeNil)
+			So(string(bytes), ShouldContainSubstring, "Discount *decimal.Decimal\n")
+		})
+	}
+}
+
+func TestImportBlock(t *testing.T) {
+	Convey("Should not emit an import declaration without imports", t, func() {
+		So(importBlock(map[string]bool{}), ShouldEqual, "")
+	})
+	Convey("Should emit a single import without parentheses", t, func() {
+		So(importBlock(map[string]bool{"time": true}), ShouldEqual, "\nimport \"time\"\n")
+	})
+	Convey("Should group the standard library before other packages", t, func() {
+		imports := map[string]bool{"gopkg.in/guregu/null.v4": true, "time": true, "database/sql": true, "github.com/google/uuid": true}
+		So(importBlock(imports), ShouldEqual, "\nimport (\n\t\"database/sql\"\n\t\"time\"\n\n\t\"github.com/google/uuid\"\n\t\"gopkg.in/guregu/null.v4\"\n)\n")
+	})
+}
+
+func TestGeneratedImportsCompile(t *testing.T) {
+	table := &Table{Name: "users", Columns: []*Column{
+		{Name: "id", DataType: "int", ColumnType: "int(11)", Key: KeyPrimary},
+		{Name: "name", DataType: "varchar", ColumnType: "varchar(255)", Nullable: true},
+		{Name: "created", DataType: "datetime", ColumnType: "datetime"},
+		{Name: "deleted", DataType: "datetime", ColumnType: "datetime", Nullable: true},
+		{Name: "settings", DataType: "json", ColumnType: "json"},
+	}}
+	bytes, err := GenerateStruct(table, "", GenerateOptions{
+		PackageName: "test",
+		Tags:        []string{TagJSON},
+		TypeMap:     TypeMap{"json": {Type: "json.RawMessage", Import: "encoding/json"}},
+	})
+	Convey("Should generate a file which compiles without adding imports", t, func() {
+		So(err, ShouldBeNil)
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "users.go", bytes, 0)
+		So(err, ShouldBeNil)
+		config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+		_, err = config.Check("test", fset, []*ast.File{file}, nil)
+		So(err, ShouldBeNil)
+	})
+}
@@ -3,6 +3,7 @@ package db2struct
 import (
 	"fmt"
 	"go/format"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -14,7 +15,14 @@ const (
 	gureguNullInt    = "null.Int"
 	sqlNullInt       = "sql.NullInt64"
 	golangInt        = "int"
+	golangInt8       = "int8"
+	golangInt16      = "int16"
+	golangInt32      = "int32"
 	golangInt64      = "int64"
+	golangUint8      = "uint8"
+	golangUint16     = "uint16"
+	golangUint32     = "uint32"
+	golangUint64     = "uint64"
 	gureguNullFloat  = "null.Float"
 	sqlNullFloat     = "sql.NullFloat64"
 	golangFloat      = "float"
@@ -24,8 +32,30 @@ const (
 	sqlNullString    = "sql.NullString"
 	gureguNullTime   = "null.Time"
 	golangTime       = "time.Time"
+	sqlNullTime      = "sql.NullTime"
+	gureguNullBool   = "null.Bool"
+	sqlNullBool      = "sql.NullBool"
+	golangBool       = "bool"
+	pqStringArray    = "pq.StringArray"
+	pqInt64Array     = "pq.Int64Array"
+	pqFloat64Array   = "pq.Float64Array"
+	pqBoolArray      = "pq.BoolArray"
+	pqByteaArray     = "pq.ByteaArray"
 )
 
+// goTypeConverters maps a dialect to the function converting its columns to go types
+var goTypeConverters = map[string]func(*Column, bool) string{
+	DialectMysql: func(column *Column, gureguTypes bool) string {
+		return mysqlTypeToGoType(column.DataType, column.ColumnType, column.Nullable, gureguTypes)
+	},
+	DialectPostgres: func(column *Column, gureguTypes bool) string {
+		return postgresTypeToGoType(column.DataType, column.Nullable, gureguTypes)
+	},
+	DialectSqlite: func(column *Column, gureguTypes bool) string {
+		return sqliteTypeToGoType(column.DataType, column.Nullable, gureguTypes)
+	},
+}
+
 // commonInitialisms is a set of common initialisms.
 // Only add entries that are highly unlikely to be non-initialisms.
 // For instance, "ID" is fine (Freudian code is rare), but "AND" is not.
@@ -82,19 +112,219 @@ var Debug = false
 // Generate Given a Column map with datatypes and a name structName,
 // attempts to generate a struct definition
 func Generate(columnTypes map[string]map[string]string, columnsSorted []string, tableName string, structName string, pkgName string, jsonAnnotation bool, gormAnnotation bool, gureguTypes bool) ([]byte, error) {
-	var dbTypes string
-	dbTypes = generateMysqlTypes(columnTypes, columnsSorted, 0, jsonAnnotation, gormAnnotation, gureguTypes)
-	src := fmt.Sprintf("package %s\ntype %s %s\n}",
-		pkgName,
+	return GenerateFromTable(tableFromColumnMap(tableName, columnTypes, columnsSorted), structName, pkgName, jsonAnnotation, gormAnnotation, gureguTypes)
+}
+
+// GenerateTables Given a Column map with datatypes per table name, attempts to generate a single file with a struct
+// definition for every table. Tables are generated in name order, struct names are derived with StructNameFromTable.
+func GenerateTables(tableColumnTypes map[string]map[string]map[string]string, tableColumnsSorted map[string][]string, pkgName string, jsonAnnotation bool, gormAnnotation bool, gureguTypes bool) ([]byte, error) {
+	tableNames := make([]string, 0, len(tableColumnTypes))
+	for table := range tableColumnTypes {
+		tableNames = append(tableNames, table)
+	}
+	sort.Strings(tableNames)
+
+	tables := make([]*Table, 0, len(tableNames))
+	for _, table := range tableNames {
+		tables = append(tables, tableFromColumnMap(table, tableColumnTypes[table], tableColumnsSorted[table]))
+	}
+	return GenerateFromTables(tables, pkgName, jsonAnnotation, gormAnnotation, gureguTypes)
+}
+
+// GenerateFromTable Given a Table and a name structName, attempts to generate a struct definition
+func GenerateFromTable(table *Table, structName string, pkgName string, jsonAnnotation bool, gormAnnotation bool, gureguTypes bool) ([]byte, error) {
+	return GenerateStruct(table, structName, legacyGenerateOptions(pkgName, jsonAnnotation, gormAnnotation, gureguTypes))
+}
+
+// GenerateFromTables Given a list of Tables, attempts to generate a single file with a struct definition for every
+// table in the order of the list. Struct names are derived with StructNameFromTable.
+func GenerateFromTables(tables []*Table, pkgName string, jsonAnnotation bool, gormAnnotation bool, gureguTypes bool) ([]byte, error) {
+	return GenerateStructs(tables, legacyGenerateOptions(pkgName, jsonAnnotation, gormAnnotation, gureguTypes))
+}
+
+// GenerateStruct Given a Table and a name structName, attempts to generate a struct definition configured by the options.
+// If structName is empty it is derived from the table name with the StructName option.
+func GenerateStruct(table *Table, structName string, options GenerateOptions) ([]byte, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	if structName == "" {
+		structName = options.structName(table.Name)
+	}
+	imports := make(map[string]bool)
+	src, err := generateStruct(table, structName, &options, imports)
+	if err != nil {
+		return nil, err
+	}
+	return formatSource(options.header() + importBlock(imports) + src)
+}
+
+// GenerateStructs Given a list of Tables, attempts to generate a single file with a struct definition for every
+// table in the order of the list configured by the options. Struct names are derived with the StructName option, the
+// JoinTables of the options are left out.
+func GenerateStructs(tables []*Table, options GenerateOptions) ([]byte, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	src := ""
+	imports := make(map[string]bool)
+	for _, table := range tables {
+		if options.IsJoinTable(table) {
+			continue
+		}
+		struc, err := generateStruct(table, options.structName(table.Name), &options, imports)
+		if err != nil {
+			return nil, err
+		}
+		src += "\n\n" + struc
+	}
+	return formatSource(options.header() + importBlock(imports) + src)
+}
+
+// generateStruct generates the unformatted struct definition, and its TableName and PrimaryKey methods and New function
+// if requested, of a table and adds the import paths of its field types to imports
+func generateStruct(table *Table, structName string, options *GenerateOptions, imports map[string]bool) (string, error) {
+	dbTypes, decls, fieldTypes, err := generateTypes(table, structName, 0, options, imports)
+	if err != nil {
+		return "", err
+	}
+	for _, relation := range tableRelations(table, structName, options) {
+		dbTypes += fmt.Sprintf("\n%s %s", relation.name, relation.fieldType())
+		if annotations := relation.tags(options); len(annotations) > 0 {
+			dbTypes += " " + structTag(annotations)
+		}
+	}
+	src := fmt.Sprintf("type %s %s\n}",
 		structName,
 		dbTypes)
-	if gormAnnotation == true {
+	if options.TableNameMethod {
 		tableNameFunc := "// TableName sets the insert table name for this struct type\n" +
 			"func (" + strings.ToLower(string(structName[0])) + " *" + structName + ") TableName() string {\n" +
-			"	return \"" + tableName + "\"" +
+			"	return \"" + table.Name + "\"" +
 			"}"
 		src = fmt.Sprintf("%s\n%s", src, tableNameFunc)
 	}
+	if options.Constructor {
+		decls = append([]typeDeclaration{tableConstructor(table, structName, fieldTypes, decls, options)}, decls...)
+	}
+	if options.PrimaryKeyMethod {
+		if key, ok := tablePrimaryKey(table, structName, fieldTypes, options); ok {
+			decls = append([]typeDeclaration{key}, decls...)
+		}
+	}
+	for _, decl := range decls {
+		src = fmt.Sprintf("%s\n\n%s", src, decl.generate(imports))
+	}
+	return src, nil
+}
+
+// typeDeclaration is a type generated for a column, which is declared after the struct
+type typeDeclaration interface {
+	// generate returns the unformatted declaration and adds the imports it requires
+	generate(imports map[string]bool) string
+}
+
+// Generate go struct entries for the columns of a table, columns of unknown data types get the fallback type. Enum
+// and set columns, and json columns with samples, get a type named after the struct and field, which is returned to
+// be declared with the struct. The go types of the fields are returned by column name.
+func generateTypes(table *Table, structName string, depth int, options *GenerateOptions, imports map[string]bool) (string, []typeDeclaration, map[string]string, error) {
+	structure := "struct {"
+	var decls []typeDeclaration
+	fieldTypes := make(map[string]string, len(table.Columns))
+
+	for _, column := range table.Columns {
+		key := column.Name
+		fieldName := options.fieldName(key)
+
+		// Get the corresponding go value type for this mysql type
+		var valueType string
+		// If the guregu (https://github.com/guregu/null) null types are requested use them, otherwise use go's sql.NullX
+
+		if mapping, ok := options.TypeMap.lookup(table, column); ok {
+			valueType = mapping.goType(column.Nullable)
+			if mapping.Import != "" {
+				imports[mapping.Import] = true
+			}
+			if column.Nullable && mapping.NullableType == "" {
+				valueType = options.nullableFieldType(valueType, imports)
+			}
+		} else if decl, name, err := columnTypeDeclaration(table, column, structName+fieldName); err != nil {
+			return "", nil, nil, err
+		} else if decl != nil {
+			decls = append(decls, decl)
+			valueType = name
+			if column.Nullable {
+				valueType = options.nullableFieldType(valueType, imports)
+			}
+		} else if column.Nullable && options.wrapsNullable() {
+			// convert the column as if it was not nullable and wrap its type
+			notNull := *column
+			notNull.Nullable = false
+			if valueType = goTypeConverter(table.dialect())(&notNull, false); valueType != "" {
+				valueType = options.nullableType(valueType, imports)
+			}
+		} else if valueType = goTypeConverter(table.dialect())(column, options.NullTypes == NullTypesGuregu); valueType != "" && column.Nullable {
+			// converters return database/sql or guregu null types, types without one are pointers
+			valueType = options.nullableFieldType(valueType, imports)
+		}
+		if valueType == "" {
+			if options.FallbackType == "" {
+				return "", nil, nil, fmt.Errorf("column %s of table %s has the unknown %s data type %q, set a fallback type to generate it anyway",
+					column.Name, table.Name, table.dialect(), column.DataType)
+			}
+			valueType = options.FallbackType
+			if column.Nullable && !holdsNull(valueType) {
+				valueType = options.nullableFieldType(valueType, imports)
+			}
+		}
+		addTypeImports(valueType, imports)
+		fieldTypes[column.Name] = valueType
+
+		var annotations []string
+		field := TagField{Table: table, Column: column, Name: fieldName, Type: valueType, OmitEmpty: options.OmitEmpty && column.Nullable}
+		for _, tag := range options.Tags {
+			emitter, _ := tagEmitter(tag)
+			if value := emitter(field); value != "" {
+				annotations = append(annotations, tag+":"+strconv.Quote(value))
+			}
+		}
+
+		if len(annotations) > 0 {
+			structure += fmt.Sprintf("\n%s %s %s", fieldName, valueType, structTag(annotations))
+			// add colulmn comment
+			if comment := column.Comment; comment != "" {
+				structure += "  //" + comment
+			}
+		} else {
+			structure += fmt.Sprintf("\n%s %s", fieldName, valueType)
+		}
+	}
+	return structure, decls, fieldTypes, nil
+}
+
+// structTag returns the struct tag literal of the annotations, a raw string unless they contain a backtick
+func structTag(annotations []string) string {
+	tag := strings.Join(annotations, " ")
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// columnTypeDeclaration returns the type generated for an enum, set or sampled json column with the given name, nil
+// for other columns
+func columnTypeDeclaration(table *Table, column *Column, name string) (typeDeclaration, string, error) {
+	if enum, ok := columnEnumType(table, column, name); ok {
+		return enum, name, nil
+	}
+	if jsonType, ok, err := columnJSONType(table, column, name); err != nil || ok {
+		return jsonType, name, err
+	}
+	return nil, "", nil
+}
+
+// formatSource formats the generated go source
+func formatSource(src string) ([]byte, error) {
 	formatted, err := format.Source([]byte(src))
 	if err != nil {
 		err = fmt.Errorf("error formatting: %s, was formatting\n%s", err, src)
@@ -102,6 +332,14 @@ func Generate(columnTypes map[string]map[string]string, columnsSorted []string,
 	return formatted, err
 }
 
+// goTypeConverter returns the type converter for the given table dialect
+func goTypeConverter(dialect string) func(*Column, bool) string {
+	if converter, ok := goTypeConverters[dialect]; ok {
+		return converter
+	}
+	return goTypeConverters[DialectMysql]
+}
+
 // fmtFieldName formats a string as a struct key
 //
 // Example:
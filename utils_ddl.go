@@ -0,0 +1,1506 @@
+package db2struct
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GetColumnsFromMysqlDDL Parse the statements of a mysql DDL script and return map of map for the table
+//
+// The returned column details match the ones returned by GetColumnsFromMysqlTable. CREATE, ALTER, DROP and RENAME
+// statements of tables and indexes are applied in order, all other statements are ignored.
+func GetColumnsFromMysqlDDL(ddl io.Reader, mysqlTable string) (*map[string]map[string]string, []string, error) {
+	table, err := DescribeMysqlDDL(ddl, mysqlTable)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columnDataTypes, columnNamesSorted := table.columnMap()
+	return &columnDataTypes, columnNamesSorted, nil
+}
+
+// DescribeMysqlDDL Execute the CREATE TABLE, ALTER TABLE and other schema statements of a mysql DDL script and describe the table
+func DescribeMysqlDDL(ddl io.Reader, mysqlTable string) (*Table, error) {
+	schema := newDDLSchema()
+	if err := schema.execReader(ddl); err != nil {
+		return nil, err
+	}
+
+	table := schema.table(mysqlTable)
+	if table == nil {
+		return nil, fmt.Errorf("table %s is not created by the DDL", mysqlTable)
+	}
+	return schema.describe(table), nil
+}
+
+// ddlTokenKind is the kind of a lexed DDL token
+type ddlTokenKind int
+
+const (
+	ddlWord   ddlTokenKind = iota // unquoted identifier or keyword
+	ddlIdent                      // backtick quoted identifier
+	ddlString                     // single or double quoted string literal
+	ddlNumber                     // numeric literal
+	ddlSymbol                     // any other single character
+)
+
+// ddlToken is a single lexed DDL token, string literals and quoted identifiers are unquoted
+type ddlToken struct {
+	kind ddlTokenKind
+	text string
+	line int
+}
+
+// ddlTypeAliases maps mysql data type synonyms to the data type reported by INFORMATION_SCHEMA
+var ddlTypeAliases = map[string]string{
+	"bool":      "tinyint",
+	"boolean":   "tinyint",
+	"integer":   "int",
+	"int1":      "tinyint",
+	"int2":      "smallint",
+	"int3":      "mediumint",
+	"int4":      "int",
+	"int8":      "bigint",
+	"middleint": "mediumint",
+	"dec":       "decimal",
+	"numeric":   "decimal",
+	"fixed":     "decimal",
+	"real":      "double",
+	"float4":    "float",
+	"float8":    "double",
+	"character": "char",
+	"nchar":     "char",
+	"nvarchar":  "varchar",
+	"serial":    "bigint",
+}
+
+// ddlColumn is a column of a table created by DDL statements
+type ddlColumn struct {
+	name         string
+	dataType     string
+	columnType   string
+	notNull      bool
+	primary      bool
+	unique       bool
+	comment      string
+	defaultValue *string
+	extra        []string
+	// generationExpression is the expression of generated columns
+	generationExpression string
+}
+
+// ddlIndex is an index of a table created by DDL statements
+type ddlIndex struct {
+	name    string
+	unique  bool
+	columns []string
+	// lengths are the prefix lengths of the columns indexed by a prefix by lower case column name
+	lengths map[string]int64
+}
+
+// ddlForeignKey is a foreign key of a table created by DDL statements
+type ddlForeignKey struct {
+	name              string
+	columns           []string
+	referencedTable   string
+	referencedColumns []string
+	onDelete          string
+	onUpdate          string
+}
+
+// ddlTable is a table created by DDL statements
+type ddlTable struct {
+	name        string
+	columns     []*ddlColumn
+	primaryKey  []string
+	indexes     []*ddlIndex
+	foreignKeys []*ddlForeignKey
+}
+
+// ddlSchema is the set of tables created by executing DDL statements
+type ddlSchema struct {
+	tables []*ddlTable
+}
+
+func newDDLSchema() *ddlSchema {
+	return &ddlSchema{}
+}
+
+// table returns the table with the given, optionally database qualified, name or nil
+func (s *ddlSchema) table(name string) *ddlTable {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, table := range s.tables {
+		if strings.EqualFold(table.name, name) {
+			return table
+		}
+	}
+	return nil
+}
+
+// describe returns the table the way DescribeMysqlTable would describe it, including the foreign keys of the other
+// tables referencing it
+func (s *ddlSchema) describe(table *ddlTable) *Table {
+	described := table.toTable()
+	for _, other := range s.tables {
+		for _, foreignKey := range other.foreignKeys {
+			if strings.EqualFold(foreignKey.referencedTable, table.name) {
+				described.ReferencedBy = append(described.ReferencedBy, foreignKey.toForeignKey(other.name))
+			}
+		}
+	}
+	return described
+}
+
+// toForeignKey returns the foreign key of the table the way INFORMATION_SCHEMA reports it
+func (f *ddlForeignKey) toForeignKey(table string) *ForeignKey {
+	return &ForeignKey{
+		Name:              f.name,
+		Table:             table,
+		Columns:           f.columns,
+		ReferencedTable:   f.referencedTable,
+		ReferencedColumns: f.referencedColumns,
+		OnDelete:          f.onDelete,
+		OnUpdate:          f.onUpdate,
+	}
+}
+
+// columnKey returns the key of a column the way INFORMATION_SCHEMA reports it in COLUMN_KEY
+func (t *ddlTable) columnKey(column *ddlColumn) string {
+	for _, name := range t.primaryKey {
+		if strings.EqualFold(name, column.name) {
+			return KeyPrimary
+		}
+	}
+	if column.unique {
+		return KeyUnique
+	}
+	for _, index := range t.indexes {
+		if index.unique && len(index.columns) == 1 && strings.EqualFold(index.columns[0], column.name) {
+			return KeyUnique
+		}
+	}
+	for _, index := range t.indexes {
+		if len(index.columns) > 0 && strings.EqualFold(index.columns[0], column.name) {
+			return KeyMultiple
+		}
+	}
+	return ""
+}
+
+// columnMap returns the column details in the format returned by GetColumnsFromMysqlTable
+func (t *ddlTable) columnMap() (map[string]map[string]string, []string) {
+	return t.toTable().columnMap()
+}
+
+// toTable returns the table the way DescribeMysqlTable would describe it
+func (t *ddlTable) toTable() *Table {
+	table := &Table{Name: t.name, Dialect: DialectMysql}
+	for _, ddlColumn := range t.columns {
+		column := &Column{
+			Name:                 ddlColumn.name,
+			DataType:             ddlColumn.dataType,
+			ColumnType:           ddlColumn.columnType,
+			Key:                  t.columnKey(ddlColumn),
+			Default:              ddlColumn.defaultValue,
+			Extra:                strings.Join(ddlColumn.extra, " "),
+			Comment:              ddlColumn.comment,
+			GenerationExpression: ddlColumn.generationExpression,
+		}
+		column.Nullable = !ddlColumn.notNull && column.Key != KeyPrimary
+		switch column.DataType {
+		case "decimal", "float", "double":
+			column.setTypeSizes(true)
+		case "char", "varchar", "binary", "varbinary":
+			column.setTypeSizes(false)
+		}
+		table.Columns = append(table.Columns, column)
+	}
+
+	if len(t.primaryKey) > 0 {
+		table.Indexes = append(table.Indexes, &Index{Name: "PRIMARY", Columns: t.primaryKey, Unique: true, Primary: true})
+	}
+	for _, column := range t.columns {
+		if column.unique && t.index(column.name) == nil {
+			table.Indexes = append(table.Indexes, &Index{Name: column.name, Columns: []string{column.name}, Unique: true})
+		}
+	}
+	for _, index := range t.indexes {
+		described := &Index{Name: index.name, Unique: index.unique}
+		for _, column := range index.columns {
+			described.addColumn(column, index.lengths[strings.ToLower(column)])
+		}
+		table.Indexes = append(table.Indexes, described)
+	}
+	sort.Slice(table.Indexes, func(i, j int) bool {
+		return strings.ToLower(table.Indexes[i].Name) < strings.ToLower(table.Indexes[j].Name)
+	})
+	for _, foreignKey := range t.foreignKeys {
+		table.ForeignKeys = append(table.ForeignKeys, foreignKey.toForeignKey(t.name))
+	}
+	sort.Slice(table.ForeignKeys, func(i, j int) bool {
+		return strings.ToLower(table.ForeignKeys[i].Name) < strings.ToLower(table.ForeignKeys[j].Name)
+	})
+	return table
+}
+
+// execReader executes all statements read from a DDL script
+func (s *ddlSchema) execReader(ddl io.Reader) error {
+	script, err := ioutil.ReadAll(ddl)
+	if err != nil {
+		return err
+	}
+	tokens, err := lexDDL(string(script))
+	if err != nil {
+		return err
+	}
+
+	start := 0
+	for i, token := range tokens {
+		if token.kind == ddlSymbol && token.text == ";" {
+			if err = s.exec(tokens[start:i]); err != nil {
+				return err
+			}
+			start = i + 1
+		}
+	}
+	return s.exec(tokens[start:])
+}
+
+// exec executes a single DDL statement, statements which do not change the schema are ignored
+func (s *ddlSchema) exec(statement []ddlToken) error {
+	p := &ddlParser{tokens: statement}
+	if p.done() {
+		return nil
+	}
+
+	var err error
+	switch {
+	case p.acceptKeyword("create"):
+		p.acceptKeyword("temporary")
+		switch {
+		case p.acceptKeyword("table"):
+			err = s.createTable(p)
+		case p.peekKeyword(0, "unique", "fulltext", "spatial", "index"):
+			err = s.createIndex(p)
+		}
+	case p.acceptKeyword("alter"):
+		p.acceptKeyword("online")
+		p.acceptKeyword("ignore")
+		if p.acceptKeyword("table") {
+			err = s.alterTable(p)
+		}
+	case p.acceptKeyword("drop"):
+		p.acceptKeyword("temporary")
+		switch {
+		case p.acceptKeyword("table"):
+			err = s.dropTable(p)
+		case p.acceptKeyword("index"):
+			err = s.dropIndex(p)
+		}
+	case p.acceptKeyword("rename", "table"):
+		err = s.renameTable(p)
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing DDL on line %d: %s", statement[0].line, err)
+	}
+	return nil
+}
+
+// createTable executes a CREATE TABLE statement
+func (s *ddlSchema) createTable(p *ddlParser) error {
+	ifNotExists := p.acceptKeyword("if", "not", "exists")
+	name, err := p.tableName()
+	if err != nil {
+		return err
+	}
+	if s.table(name) != nil {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("table %s already exists", name)
+	}
+
+	// CREATE TABLE ... SELECT derives the columns from a query
+	if p.peekKeyword(0, "as", "select") {
+		return nil
+	}
+
+	table := &ddlTable{name: name}
+	if p.acceptKeyword("like") || (p.peekSymbol("(") && p.peekKeyword(1, "like")) {
+		parens := p.accept("(")
+		p.acceptKeyword("like")
+		likeName, err := p.tableName()
+		if err != nil {
+			return err
+		}
+		if parens {
+			if err = p.expect(")"); err != nil {
+				return err
+			}
+		}
+		like := s.table(likeName)
+		if like == nil {
+			return fmt.Errorf("table %s does not exist", likeName)
+		}
+		table = like.copy(name)
+	} else {
+		if err = p.expect("("); err != nil {
+			return err
+		}
+		for {
+			if err = table.createDefinition(p); err != nil {
+				return err
+			}
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err = p.expect(")"); err != nil {
+			return err
+		}
+	}
+
+	s.tables = append(s.tables, table)
+	return nil
+}
+
+// createIndex executes a CREATE INDEX statement
+func (s *ddlSchema) createIndex(p *ddlParser) error {
+	unique := p.acceptKeyword("unique")
+	if !unique && !p.acceptKeyword("fulltext") {
+		p.acceptKeyword("spatial")
+	}
+	if !p.acceptKeyword("index") {
+		return fmt.Errorf("expected INDEX but found %q", p.peek().text)
+	}
+	name := p.indexName()
+	if !p.acceptKeyword("on") {
+		return fmt.Errorf("expected ON but found %q", p.peek().text)
+	}
+	table, err := s.existingTable(p)
+	if err != nil {
+		return err
+	}
+	columns, lengths, err := p.indexPrefixColumns()
+	if err != nil {
+		return err
+	}
+	table.addIndex(&ddlIndex{name: name, unique: unique, columns: columns, lengths: lengths})
+	return nil
+}
+
+// dropTable executes a DROP TABLE statement
+func (s *ddlSchema) dropTable(p *ddlParser) error {
+	ifExists := p.acceptKeyword("if", "exists")
+	for {
+		name, err := p.tableName()
+		if err != nil {
+			return err
+		}
+		table := s.table(name)
+		if table == nil && !ifExists {
+			return fmt.Errorf("table %s does not exist", name)
+		}
+		for i := range s.tables {
+			if s.tables[i] == table {
+				s.tables = append(s.tables[:i], s.tables[i+1:]...)
+				break
+			}
+		}
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+// dropIndex executes a DROP INDEX statement
+func (s *ddlSchema) dropIndex(p *ddlParser) error {
+	name, err := p.identifier()
+	if err != nil {
+		return err
+	}
+	if !p.acceptKeyword("on") {
+		return fmt.Errorf("expected ON but found %q", p.peek().text)
+	}
+	table, err := s.existingTable(p)
+	if err != nil {
+		return err
+	}
+	return table.dropIndex(name)
+}
+
+// renameTable executes a RENAME TABLE statement
+func (s *ddlSchema) renameTable(p *ddlParser) error {
+	for {
+		table, err := s.existingTable(p)
+		if err != nil {
+			return err
+		}
+		if !p.acceptKeyword("to") {
+			return fmt.Errorf("expected TO but found %q", p.peek().text)
+		}
+		if err = s.renameTo(table, p); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+// renameTo consumes the new name of a renamed table
+func (s *ddlSchema) renameTo(table *ddlTable, p *ddlParser) error {
+	name, err := p.tableName()
+	if err != nil {
+		return err
+	}
+	if s.table(name) != nil {
+		return fmt.Errorf("table %s already exists", name)
+	}
+	for _, other := range s.tables {
+		for _, foreignKey := range other.foreignKeys {
+			if strings.EqualFold(foreignKey.referencedTable, table.name) {
+				foreignKey.referencedTable = name
+			}
+		}
+	}
+	table.name = name
+	return nil
+}
+
+// existingTable consumes a table name and returns the table
+func (s *ddlSchema) existingTable(p *ddlParser) (*ddlTable, error) {
+	name, err := p.tableName()
+	if err != nil {
+		return nil, err
+	}
+	table := s.table(name)
+	if table == nil {
+		return nil, fmt.Errorf("table %s does not exist", name)
+	}
+	return table, nil
+}
+
+// alterTable executes an ALTER TABLE statement
+func (s *ddlSchema) alterTable(p *ddlParser) error {
+	table, err := s.existingTable(p)
+	if err != nil {
+		return err
+	}
+	for !p.done() {
+		if err = s.alterSpecification(table, p); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	return nil
+}
+
+// alterSpecification executes a single change of an ALTER TABLE statement
+func (s *ddlSchema) alterSpecification(table *ddlTable, p *ddlParser) error {
+	switch {
+	case p.acceptKeyword("add"):
+		if !p.acceptKeyword("column") && p.peek().kind != ddlIdent && p.peekKeyword(0, "constraint", "primary", "unique", "key", "index", "fulltext", "spatial", "foreign", "check") {
+			return table.createDefinition(p)
+		}
+		p.acceptKeyword("if", "not", "exists")
+		if p.accept("(") {
+			for {
+				if err := table.alterColumn(p, ""); err != nil {
+					return err
+				}
+				if !p.accept(",") {
+					break
+				}
+			}
+			return p.expect(")")
+		}
+		return table.alterColumn(p, "")
+	case p.acceptKeyword("drop"):
+		switch {
+		case p.acceptKeyword("primary", "key"):
+			table.primaryKey = nil
+		case p.acceptKeyword("index"), p.acceptKeyword("key"):
+			p.acceptKeyword("if", "exists")
+			name, err := p.identifier()
+			if err != nil {
+				return err
+			}
+			return table.dropIndex(name)
+		case p.acceptKeyword("foreign", "key"), p.acceptKeyword("check"), p.acceptKeyword("constraint"):
+			p.acceptKeyword("if", "exists")
+			name, err := p.identifier()
+			if err != nil {
+				return err
+			}
+			table.dropForeignKey(name)
+		default:
+			p.acceptKeyword("column")
+			ifExists := p.acceptKeyword("if", "exists")
+			name, err := p.identifier()
+			if err != nil {
+				return err
+			}
+			if table.column(name) == nil {
+				if ifExists {
+					return nil
+				}
+				return fmt.Errorf("column %s.%s does not exist", table.name, name)
+			}
+			table.dropColumn(name)
+		}
+	case p.acceptKeyword("modify"):
+		p.acceptKeyword("column")
+		p.acceptKeyword("if", "exists")
+		return table.alterColumn(p, p.peek().text)
+	case p.acceptKeyword("change"):
+		p.acceptKeyword("column")
+		p.acceptKeyword("if", "exists")
+		name, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		return table.alterColumn(p, name)
+	case p.acceptKeyword("rename"):
+		switch {
+		case p.acceptKeyword("column"):
+			from, err := p.identifier()
+			if err != nil {
+				return err
+			}
+			if !p.acceptKeyword("to") {
+				return fmt.Errorf("expected TO but found %q", p.peek().text)
+			}
+			to, err := p.identifier()
+			if err != nil {
+				return err
+			}
+			column := table.column(from)
+			if column == nil {
+				return fmt.Errorf("column %s.%s does not exist", table.name, from)
+			}
+			table.renameColumn(column, to)
+		case p.acceptKeyword("index"), p.acceptKeyword("key"):
+			from, err := p.identifier()
+			if err != nil {
+				return err
+			}
+			if !p.acceptKeyword("to") {
+				return fmt.Errorf("expected TO but found %q", p.peek().text)
+			}
+			to, err := p.identifier()
+			if err != nil {
+				return err
+			}
+			for _, index := range table.indexes {
+				if strings.EqualFold(index.name, from) {
+					index.name = to
+				}
+			}
+		default:
+			if !p.acceptKeyword("to") {
+				p.acceptKeyword("as")
+			}
+			return s.renameTo(table, p)
+		}
+	case p.acceptKeyword("alter"):
+		if p.acceptKeyword("index") {
+			p.skipIndexOptions()
+			return nil
+		}
+		p.acceptKeyword("column")
+		name, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		column := table.column(name)
+		if column == nil {
+			return fmt.Errorf("column %s.%s does not exist", table.name, name)
+		}
+		switch {
+		case p.acceptKeyword("set", "default"):
+			value, err := p.defaultValue()
+			if err != nil {
+				return err
+			}
+			column.defaultValue = value
+		case p.acceptKeyword("drop", "default"):
+			column.defaultValue = nil
+		}
+		p.skipIndexOptions()
+	default:
+		// Table options, such as ENGINE or CONVERT TO CHARACTER SET, do not change the columns
+		p.skipIndexOptions()
+	}
+	return nil
+}
+
+// alterColumn consumes a column definition and its position, the column replaces the column named replaces
+// or is added to the table if replaces is empty
+func (t *ddlTable) alterColumn(p *ddlParser, replaces string) error {
+	column, err := p.columnDefinition()
+	if err != nil {
+		return err
+	}
+
+	position := len(t.columns)
+	if replaces != "" {
+		old := t.column(replaces)
+		if old == nil {
+			return fmt.Errorf("column %s.%s does not exist", t.name, replaces)
+		}
+		for i := range t.columns {
+			if t.columns[i] == old {
+				position = i
+			}
+		}
+		t.columns = append(t.columns[:position], t.columns[position+1:]...)
+		t.renameColumn(old, column.name)
+	} else if t.column(column.name) != nil {
+		return fmt.Errorf("column %s.%s already exists", t.name, column.name)
+	}
+
+	switch {
+	case p.acceptKeyword("first"):
+		position = 0
+	case p.acceptKeyword("after"):
+		name, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		after := t.column(name)
+		if after == nil {
+			return fmt.Errorf("column %s.%s does not exist", t.name, name)
+		}
+		for i := range t.columns {
+			if t.columns[i] == after {
+				position = i + 1
+			}
+		}
+	}
+
+	t.columns = append(t.columns, nil)
+	copy(t.columns[position+1:], t.columns[position:])
+	t.columns[position] = column
+	if column.primary {
+		t.primaryKey = []string{column.name}
+	}
+	return nil
+}
+
+// renameColumn renames a column and all references to it in keys
+func (t *ddlTable) renameColumn(column *ddlColumn, name string) {
+	for i := range t.primaryKey {
+		if strings.EqualFold(t.primaryKey[i], column.name) {
+			t.primaryKey[i] = name
+		}
+	}
+	for _, index := range t.indexes {
+		for i := range index.columns {
+			if strings.EqualFold(index.columns[i], column.name) {
+				index.columns[i] = name
+			}
+		}
+		if length, ok := index.lengths[strings.ToLower(column.name)]; ok {
+			delete(index.lengths, strings.ToLower(column.name))
+			index.lengths[strings.ToLower(name)] = length
+		}
+	}
+	for _, foreignKey := range t.foreignKeys {
+		for i := range foreignKey.columns {
+			if strings.EqualFold(foreignKey.columns[i], column.name) {
+				foreignKey.columns[i] = name
+			}
+		}
+	}
+	column.name = name
+}
+
+// dropColumn removes a column and all references to it in keys, indexes without columns and foreign keys of the
+// column are dropped
+func (t *ddlTable) dropColumn(name string) {
+	t.primaryKey = removeDDLName(t.primaryKey, name)
+	foreignKeys := t.foreignKeys[:0]
+	for _, foreignKey := range t.foreignKeys {
+		if !hasDDLName(foreignKey.columns, name) {
+			foreignKeys = append(foreignKeys, foreignKey)
+		}
+	}
+	t.foreignKeys = foreignKeys
+	indexes := t.indexes[:0]
+	for _, index := range t.indexes {
+		index.columns = removeDDLName(index.columns, name)
+		delete(index.lengths, strings.ToLower(name))
+		if len(index.columns) > 0 {
+			indexes = append(indexes, index)
+		}
+	}
+	t.indexes = indexes
+	columns := t.columns[:0]
+	for _, column := range t.columns {
+		if !strings.EqualFold(column.name, name) {
+			columns = append(columns, column)
+		}
+	}
+	t.columns = columns
+}
+
+// hasDDLName reports whether the names contain name
+func hasDDLName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeDDLName returns the names without name
+func removeDDLName(names []string, name string) []string {
+	kept := names[:0]
+	for _, n := range names {
+		if !strings.EqualFold(n, name) {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// addIndex adds an index, unnamed indexes are named after their first column the way mysql names them
+func (t *ddlTable) addIndex(index *ddlIndex) {
+	if index.name == "" && len(index.columns) > 0 {
+		index.name = index.columns[0]
+		for i := 2; t.index(index.name) != nil; i++ {
+			index.name = fmt.Sprintf("%s_%d", index.columns[0], i)
+		}
+	}
+	t.indexes = append(t.indexes, index)
+}
+
+// dropForeignKey removes the foreign key with the given name, if any
+func (t *ddlTable) dropForeignKey(name string) {
+	for i, foreignKey := range t.foreignKeys {
+		if strings.EqualFold(foreignKey.name, name) {
+			t.foreignKeys = append(t.foreignKeys[:i], t.foreignKeys[i+1:]...)
+			return
+		}
+	}
+}
+
+// index returns the index with the given name or nil
+func (t *ddlTable) index(name string) *ddlIndex {
+	for _, index := range t.indexes {
+		if strings.EqualFold(index.name, name) {
+			return index
+		}
+	}
+	return nil
+}
+
+// dropIndex removes the index with the given name
+func (t *ddlTable) dropIndex(name string) error {
+	for i, index := range t.indexes {
+		if strings.EqualFold(index.name, name) {
+			t.indexes = append(t.indexes[:i], t.indexes[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("index %s does not exist on table %s", name, t.name)
+}
+
+// column returns the column with the given name or nil
+func (t *ddlTable) column(name string) *ddlColumn {
+	for _, column := range t.columns {
+		if strings.EqualFold(column.name, name) {
+			return column
+		}
+	}
+	return nil
+}
+
+// copy returns a deep copy of the table with a new name
+func (t *ddlTable) copy(name string) *ddlTable {
+	table := &ddlTable{name: name, primaryKey: append([]string{}, t.primaryKey...)}
+	for _, column := range t.columns {
+		c := *column
+		c.extra = append([]string{}, column.extra...)
+		table.columns = append(table.columns, &c)
+	}
+	for _, index := range t.indexes {
+		lengths := make(map[string]int64, len(index.lengths))
+		for column, length := range index.lengths {
+			lengths[column] = length
+		}
+		table.indexes = append(table.indexes, &ddlIndex{name: index.name, unique: index.unique, columns: append([]string{}, index.columns...), lengths: lengths})
+	}
+	return table
+}
+
+// createDefinition parses a column, key or constraint definition of a CREATE TABLE statement
+func (t *ddlTable) createDefinition(p *ddlParser) error {
+	if p.peek().kind == ddlIdent || !p.peekKeyword(0, "constraint", "primary", "unique", "key", "index", "fulltext", "spatial", "foreign", "check") {
+		column, err := p.columnDefinition()
+		if err != nil {
+			return err
+		}
+		t.columns = append(t.columns, column)
+		if column.primary {
+			t.primaryKey = []string{column.name}
+		}
+		return nil
+	}
+
+	constraintName := ""
+	if p.acceptKeyword("constraint") {
+		if !p.peekKeyword(0, "primary", "unique", "foreign", "check") {
+			name, err := p.identifier()
+			if err != nil {
+				return err
+			}
+			constraintName = name
+		}
+	}
+
+	switch {
+	case p.acceptKeyword("primary", "key"):
+		p.indexName()
+		columns, err := p.indexColumns()
+		if err != nil {
+			return err
+		}
+		t.primaryKey = columns
+	case p.acceptKeyword("unique"):
+		if !p.acceptKeyword("key") {
+			p.acceptKeyword("index")
+		}
+		name := p.indexName()
+		if name == "" {
+			name = constraintName
+		}
+		columns, lengths, err := p.indexPrefixColumns()
+		if err != nil {
+			return err
+		}
+		t.addIndex(&ddlIndex{name: name, unique: true, columns: columns, lengths: lengths})
+	case p.acceptKeyword("key"), p.acceptKeyword("index"):
+		name := p.indexName()
+		columns, lengths, err := p.indexPrefixColumns()
+		if err != nil {
+			return err
+		}
+		t.addIndex(&ddlIndex{name: name, columns: columns, lengths: lengths})
+	case p.acceptKeyword("fulltext"), p.acceptKeyword("spatial"):
+		if !p.acceptKeyword("key") {
+			p.acceptKeyword("index")
+		}
+		name := p.indexName()
+		columns, lengths, err := p.indexPrefixColumns()
+		if err != nil {
+			return err
+		}
+		t.addIndex(&ddlIndex{name: name, columns: columns, lengths: lengths})
+	case p.acceptKeyword("foreign", "key"):
+		p.indexName()
+		columns, err := p.indexColumns()
+		if err != nil {
+			return err
+		}
+		foreignKey, err := p.references()
+		if err != nil {
+			return err
+		}
+		foreignKey.name = constraintName
+		foreignKey.columns = columns
+		if foreignKey.name == "" {
+			// unnamed foreign keys are named the way InnoDB names them
+			foreignKey.name = fmt.Sprintf("%s_ibfk_%d", t.name, len(t.foreignKeys)+1)
+		}
+		t.foreignKeys = append(t.foreignKeys, foreignKey)
+		// InnoDB creates an index for foreign keys without one
+		for _, index := range t.indexes {
+			if len(index.columns) >= len(columns) && strings.EqualFold(index.columns[0], columns[0]) {
+				return nil
+			}
+		}
+		if len(t.primaryKey) == 0 || !strings.EqualFold(t.primaryKey[0], columns[0]) {
+			t.addIndex(&ddlIndex{name: constraintName, columns: columns})
+		}
+	case p.acceptKeyword("check"):
+		return p.skipGroup()
+	default:
+		return fmt.Errorf("unexpected %q in table definition", p.peek().text)
+	}
+	p.skipIndexOptions()
+	return nil
+}
+
+// ddlParser parses the tokens of a single DDL statement
+type ddlParser struct {
+	tokens []ddlToken
+	pos    int
+}
+
+func (p *ddlParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *ddlParser) peek() ddlToken {
+	if p.done() {
+		return ddlToken{kind: ddlSymbol}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *ddlParser) next() ddlToken {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+// peekKeyword reports whether the token at offset from the current position is one of the keywords
+func (p *ddlParser) peekKeyword(offset int, keywords ...string) bool {
+	if p.pos+offset >= len(p.tokens) {
+		return false
+	}
+	token := p.tokens[p.pos+offset]
+	if token.kind != ddlWord {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(token.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptKeyword consumes the sequence of keywords if the next tokens match it
+func (p *ddlParser) acceptKeyword(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if !p.peekKeyword(i, keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func (p *ddlParser) peekSymbol(symbol string) bool {
+	token := p.peek()
+	return !p.done() && token.kind == ddlSymbol && token.text == symbol
+}
+
+// accept consumes the symbol if it is the next token
+func (p *ddlParser) accept(symbol string) bool {
+	if p.peekSymbol(symbol) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *ddlParser) expect(symbol string) error {
+	if !p.accept(symbol) {
+		if p.done() {
+			return fmt.Errorf("expected %q but the statement ended", symbol)
+		}
+		return fmt.Errorf("expected %q but found %q", symbol, p.peek().text)
+	}
+	return nil
+}
+
+// identifier consumes a quoted or unquoted identifier
+func (p *ddlParser) identifier() (string, error) {
+	token := p.peek()
+	if p.done() || (token.kind != ddlWord && token.kind != ddlIdent) {
+		return "", fmt.Errorf("expected an identifier but found %q", token.text)
+	}
+	p.pos++
+	return token.text, nil
+}
+
+// tableName consumes a table name, dropping the database qualifier
+func (p *ddlParser) tableName() (string, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return "", err
+	}
+	if p.accept(".") {
+		return p.identifier()
+	}
+	return name, nil
+}
+
+// skipGroup consumes a parenthesized group including all nested groups
+func (p *ddlParser) skipGroup() error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for depth := 1; depth > 0; {
+		if p.done() {
+			return fmt.Errorf("unbalanced parentheses")
+		}
+		token := p.next()
+		if token.kind == ddlSymbol {
+			switch token.text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+		}
+	}
+	return nil
+}
+
+// indexName consumes the optional name and USING clause in front of an index column list
+func (p *ddlParser) indexName() string {
+	name := ""
+	if !p.peekSymbol("(") && !p.peekKeyword(0, "using") {
+		name, _ = p.identifier()
+	}
+	if p.acceptKeyword("using") {
+		p.next()
+	}
+	return name
+}
+
+// indexColumns consumes an index column list and returns the column names, expressions are skipped
+func (p *ddlParser) indexColumns() ([]string, error) {
+	columns, _, err := p.indexPrefixColumns()
+	return columns, err
+}
+
+// indexPrefixColumns consumes an index column list and returns the column names and the prefix lengths of the
+// columns indexed by a prefix, such as email(191), by lower case column name. Expressions are skipped.
+func (p *ddlParser) indexPrefixColumns() ([]string, map[string]int64, error) {
+	if err := p.expect("("); err != nil {
+		return nil, nil, err
+	}
+	columns := []string{}
+	lengths := make(map[string]int64)
+	for {
+		if p.peekSymbol("(") {
+			if err := p.skipGroup(); err != nil {
+				return nil, nil, err
+			}
+		} else {
+			name, err := p.identifier()
+			if err != nil {
+				return nil, nil, err
+			}
+			columns = append(columns, name)
+			if p.accept("(") {
+				length, err := strconv.ParseInt(p.next().text, 10, 64)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid prefix length of index column %s", name)
+				}
+				lengths[strings.ToLower(name)] = length
+				if err = p.expect(")"); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+		if !p.acceptKeyword("asc") {
+			p.acceptKeyword("desc")
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	return columns, lengths, p.expect(")")
+}
+
+// skipIndexOptions consumes the options following an index column list
+func (p *ddlParser) skipIndexOptions() {
+	for !p.done() && !p.peekSymbol(",") && !p.peekSymbol(")") {
+		if p.peekSymbol("(") {
+			p.skipGroup()
+			continue
+		}
+		p.next()
+	}
+}
+
+// references consumes a REFERENCES clause of a foreign key and returns the referenced table, columns and actions
+func (p *ddlParser) references() (*ddlForeignKey, error) {
+	if !p.acceptKeyword("references") {
+		return nil, fmt.Errorf("expected REFERENCES but found %q", p.peek().text)
+	}
+	table, err := p.tableName()
+	if err != nil {
+		return nil, err
+	}
+	columns, err := p.indexColumns()
+	if err != nil {
+		return nil, err
+	}
+	foreignKey := &ddlForeignKey{referencedTable: table, referencedColumns: columns, onDelete: "NO ACTION", onUpdate: "NO ACTION"}
+	for {
+		switch {
+		case p.acceptKeyword("match"):
+			p.next()
+		case p.acceptKeyword("on", "delete"):
+			foreignKey.onDelete = p.referentialAction()
+		case p.acceptKeyword("on", "update"):
+			foreignKey.onUpdate = p.referentialAction()
+		default:
+			return foreignKey, nil
+		}
+	}
+}
+
+// referentialAction consumes the action of an ON DELETE or ON UPDATE clause
+func (p *ddlParser) referentialAction() string {
+	switch {
+	case p.acceptKeyword("set", "null"):
+		return "SET NULL"
+	case p.acceptKeyword("set", "default"):
+		return "SET DEFAULT"
+	case p.acceptKeyword("no", "action"):
+		return "NO ACTION"
+	}
+	return strings.ToUpper(p.next().text)
+}
+
+// columnDefinition consumes a column name, its data type and its attributes
+func (p *ddlParser) columnDefinition() (*ddlColumn, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	column := &ddlColumn{name: name}
+	if err = p.dataType(column); err != nil {
+		return nil, err
+	}
+
+	for !p.done() && !p.peekSymbol(",") && !p.peekSymbol(")") && !p.peekKeyword(0, "first", "after") {
+		switch {
+		case p.acceptKeyword("not", "null"):
+			column.notNull = true
+		case p.acceptKeyword("null"):
+			column.notNull = false
+		case p.acceptKeyword("default"):
+			value, err := p.defaultValue()
+			if err != nil {
+				return nil, err
+			}
+			column.defaultValue = value
+		case p.acceptKeyword("auto_increment"):
+			column.extra = append(column.extra, "auto_increment")
+		case p.acceptKeyword("on", "update"):
+			value, err := p.defaultValue()
+			if err != nil {
+				return nil, err
+			}
+			if value == nil {
+				return nil, fmt.Errorf("ON UPDATE of column %s requires a value", column.name)
+			}
+			column.extra = append(column.extra, "on update "+strings.ToLower(*value))
+		case p.acceptKeyword("unique"):
+			p.acceptKeyword("key")
+			column.unique = true
+		case p.acceptKeyword("primary", "key"), p.acceptKeyword("key"):
+			column.notNull = true
+			column.primary = true
+		case p.acceptKeyword("comment"):
+			column.comment = p.next().text
+		case p.acceptKeyword("collate"), p.acceptKeyword("charset"), p.acceptKeyword("character", "set"),
+			p.acceptKeyword("column_format"), p.acceptKeyword("storage"), p.acceptKeyword("srid"):
+			p.accept("=")
+			p.next()
+		case p.acceptKeyword("generated", "always"):
+		case p.acceptKeyword("as"):
+			start := p.pos
+			if err = p.skipGroup(); err != nil {
+				return nil, err
+			}
+			column.generationExpression = joinDDLTokens(p.tokens[start+1 : p.pos-1])
+			generated := "VIRTUAL GENERATED"
+			if p.acceptKeyword("stored") || p.acceptKeyword("persistent") {
+				generated = "STORED GENERATED"
+			} else {
+				p.acceptKeyword("virtual")
+			}
+			column.extra = append(column.extra, generated)
+		case p.acceptKeyword("references"):
+			// InnoDB ignores inline references, they do not create a foreign key
+			p.pos--
+			if _, err = p.references(); err != nil {
+				return nil, err
+			}
+		case p.peekSymbol("("):
+			if err = p.skipGroup(); err != nil {
+				return nil, err
+			}
+		default:
+			// Attributes which do not change the column details, such as CHECK or INVISIBLE
+			p.next()
+		}
+	}
+	return column, nil
+}
+
+// dataType consumes a column data type, normalizing synonyms to the type reported by INFORMATION_SCHEMA
+func (p *ddlParser) dataType(column *ddlColumn) error {
+	token := p.next()
+	if token.kind != ddlWord {
+		return fmt.Errorf("expected a data type for column %s but found %q", column.name, token.text)
+	}
+	dataType := strings.ToLower(token.text)
+	switch dataType {
+	case "double":
+		p.acceptKeyword("precision")
+	case "national":
+		dataType = strings.ToLower(p.next().text)
+		if dataType == "varchar" {
+			break
+		}
+		fallthrough
+	case "char", "character", "nchar":
+		if p.acceptKeyword("varying") {
+			dataType = "varchar"
+		}
+	case "long":
+		switch {
+		case p.acceptKeyword("varbinary"):
+			dataType = "mediumblob"
+		default:
+			p.acceptKeyword("varchar")
+			dataType = "mediumtext"
+		}
+	}
+	if alias, ok := ddlTypeAliases[dataType]; ok {
+		dataType = alias
+	}
+
+	var args []string
+	if p.accept("(") {
+		for !p.done() && !p.peekSymbol(")") {
+			token := p.next()
+			switch token.kind {
+			case ddlString:
+				args = append(args, "'"+strings.Replace(token.text, "'", "''", -1)+"'")
+			case ddlNumber, ddlWord:
+				args = append(args, token.text)
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return err
+		}
+	}
+	switch strings.ToLower(token.text) {
+	case "bool", "boolean":
+		args = []string{"1"}
+	case "serial":
+		column.notNull = true
+		column.unique = true
+		column.extra = append(column.extra, "auto_increment")
+	}
+
+	columnType := dataType
+	if len(args) > 0 {
+		columnType += "(" + strings.Join(args, ",") + ")"
+	}
+	for {
+		switch {
+		case p.acceptKeyword("unsigned"):
+			columnType += " unsigned"
+			continue
+		case p.acceptKeyword("zerofill"):
+			columnType += " zerofill"
+			continue
+		case p.acceptKeyword("signed"):
+			continue
+		}
+		break
+	}
+	if strings.ToLower(token.text) == "serial" {
+		columnType += " unsigned"
+	}
+
+	column.dataType = dataType
+	column.columnType = columnType
+	return nil
+}
+
+// defaultValue consumes a DEFAULT or ON UPDATE value, returning nil for NULL
+func (p *ddlParser) defaultValue() (*string, error) {
+	if p.acceptKeyword("null") {
+		return nil, nil
+	}
+	if p.peekSymbol("(") {
+		start := p.pos
+		if err := p.skipGroup(); err != nil {
+			return nil, err
+		}
+		value := joinDDLTokens(p.tokens[start:p.pos])
+		return &value, nil
+	}
+
+	value := ""
+	if p.accept("-") {
+		value = "-"
+	} else {
+		p.accept("+")
+	}
+	token := p.next()
+	// charset introducers such as _utf8mb4'value'
+	if token.kind == ddlWord && strings.HasPrefix(token.text, "_") && p.peek().kind == ddlString {
+		token = p.next()
+	}
+	// bit and hex literals such as b'0' and x'0F' keep their prefix and quotes
+	if token.kind == ddlWord && (strings.EqualFold(token.text, "b") || strings.EqualFold(token.text, "x")) && p.peek().kind == ddlString {
+		value += token.text + joinDDLTokens([]ddlToken{p.next()})
+		return &value, nil
+	}
+	value += token.text
+	if token.kind == ddlWord && p.peekSymbol("(") {
+		start := p.pos
+		if err := p.skipGroup(); err != nil {
+			return nil, err
+		}
+		value += joinDDLTokens(p.tokens[start:p.pos])
+	}
+	return &value, nil
+}
+
+// joinDDLTokens formats tokens back into DDL
+func joinDDLTokens(tokens []ddlToken) string {
+	var parts []string
+	for _, token := range tokens {
+		switch token.kind {
+		case ddlString:
+			parts = append(parts, "'"+strings.Replace(token.text, "'", "''", -1)+"'")
+		case ddlIdent:
+			parts = append(parts, "`"+strings.Replace(token.text, "`", "``", -1)+"`")
+		default:
+			parts = append(parts, token.text)
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// lexDDL splits a DDL script into tokens, dropping whitespace and comments
+func lexDDL(script string) ([]ddlToken, error) {
+	var tokens []ddlToken
+	runes := []rune(script)
+	line := 1
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case unicode.IsSpace(c):
+			i++
+		case c == '#' || (c == '-' && i+1 < len(runes) && runes[i+1] == '-' && (i+2 == len(runes) || unicode.IsSpace(runes[i+2]))):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := line
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated comment starting on line %d", start)
+			}
+			i += 2
+		case c == '`' || c == '\'' || c == '"':
+			start := line
+			var text []rune
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated quote starting on line %d", start)
+				}
+				if runes[i] == c {
+					// a doubled quote is an escaped quote
+					if i+1 < len(runes) && runes[i+1] == c {
+						text = append(text, c)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				if runes[i] == '\\' && c != '`' && i+1 < len(runes) {
+					i++
+					text = append(text, unescapeDDLRune(runes[i]))
+					i++
+					continue
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				text = append(text, runes[i])
+				i++
+			}
+			kind := ddlString
+			if c == '`' {
+				kind = ddlIdent
+			}
+			tokens = append(tokens, ddlToken{kind: kind, text: string(text), line: start})
+		case unicode.IsDigit(c):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || isDDLWordRune(runes[i])) {
+				i++
+			}
+			kind := ddlNumber
+			for _, r := range runes[start:i] {
+				if !unicode.IsDigit(r) && r != '.' && r != 'e' && r != 'E' {
+					// identifiers may start with digits
+					kind = ddlWord
+				}
+			}
+			tokens = append(tokens, ddlToken{kind: kind, text: string(runes[start:i]), line: line})
+		case isDDLWordRune(c):
+			start := i
+			for i < len(runes) && (isDDLWordRune(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, ddlToken{kind: ddlWord, text: string(runes[start:i]), line: line})
+		default:
+			tokens = append(tokens, ddlToken{kind: ddlSymbol, text: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isDDLWordRune(c rune) bool {
+	return unicode.IsLetter(c) || c == '_' || c == '$'
+}
+
+// unescapeDDLRune returns the character escaped by a backslash in a string literal
+func unescapeDDLRune(c rune) rune {
+	switch c {
+	case '0':
+		return 0
+	case 'b':
+		return '\b'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'Z':
+		return 26
+	}
+	return c
+}
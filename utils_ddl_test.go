@@ -0,0 +1,258 @@
+package db2struct
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDDL = `
+-- users of the application
+CREATE TABLE IF NOT EXISTS ` + "`users`" + ` (
+	` + "`id`" + ` INT(10) UNSIGNED NOT NULL AUTO_INCREMENT,
+	` + "`email`" + ` VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL COMMENT 'login; must be unique',
+	` + "`name`" + ` VARCHAR(100) DEFAULT NULL,
+	` + "`active`" + ` BOOLEAN NOT NULL DEFAULT TRUE,
+	` + "`balance`" + ` NUMERIC(10, 2) NOT NULL DEFAULT '0.00',
+	` + "`team_id`" + ` INT UNSIGNED,
+	` + "`created_at`" + ` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	PRIMARY KEY (` + "`id`" + `),
+	UNIQUE KEY ` + "`users_email`" + ` (` + "`email`" + `(191)),
+	KEY ` + "`users_name_active`" + ` (` + "`name`" + `, ` + "`active`" + `),
+	CONSTRAINT ` + "`users_team`" + ` FOREIGN KEY (` + "`team_id`" + `) REFERENCES ` + "`teams`" + ` (` + "`id`" + `) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 /* trailing comment */;
+
+INSERT INTO users (email) VALUES ('ignored@example.com');
+
+CREATE TABLE posts (
+	id SERIAL,
+	title TEXT NOT NULL,
+	body LONG VARCHAR,
+	score DOUBLE PRECISION
+);
+`
+
+func TestGetColumnsFromMysqlDDL(t *testing.T) {
+	columnMap, columnsSorted, err := GetColumnsFromMysqlDDL(strings.NewReader(testDDL), "users")
+	Convey("Should be able to parse columns from a CREATE TABLE statement", t, func() {
+		So(err, ShouldBeNil)
+		So(columnsSorted, ShouldResemble, []string{"id", "email", "name", "active", "balance", "team_id", "created_at"})
+		So((*columnMap)["id"], ShouldResemble, map[string]string{"value": "int", "column_type": "int(10) unsigned", "nullable": "NO", "primary": "PRI", "comment": ""})
+		So((*columnMap)["email"], ShouldResemble, map[string]string{"value": "varchar", "column_type": "varchar(255)", "nullable": "NO", "primary": "UNI", "comment": "login; must be unique"})
+		So((*columnMap)["name"], ShouldResemble, map[string]string{"value": "varchar", "column_type": "varchar(100)", "nullable": "YES", "primary": "MUL", "comment": ""})
+		So((*columnMap)["active"]["value"], ShouldEqual, "tinyint")
+		So((*columnMap)["active"]["primary"], ShouldEqual, "")
+		So((*columnMap)["balance"]["value"], ShouldEqual, "decimal")
+		So((*columnMap)["team_id"]["primary"], ShouldEqual, "MUL")
+		So((*columnMap)["created_at"]["nullable"], ShouldEqual, "NO")
+	})
+
+	columnMap, _, err = GetColumnsFromMysqlDDL(strings.NewReader(testDDL), "test.posts")
+	Convey("Should normalize data type synonyms", t, func() {
+		So(err, ShouldBeNil)
+		So((*columnMap)["id"], ShouldResemble, map[string]string{"value": "bigint", "column_type": "bigint unsigned", "nullable": "NO", "primary": "UNI", "comment": ""})
+		So((*columnMap)["body"]["value"], ShouldEqual, "mediumtext")
+		So((*columnMap)["score"]["value"], ShouldEqual, "double")
+	})
+
+	_, _, err = GetColumnsFromMysqlDDL(strings.NewReader(testDDL), "comments")
+	Convey("Should get an error for a table which is not created", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+
+	_, _, err = GetColumnsFromMysqlDDL(strings.NewReader("CREATE TABLE broken (id INT"), "broken")
+	Convey("Should get an error for an incomplete statement", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+
+	_, err = DescribeMysqlDDL(strings.NewReader("CREATE TABLE t (a int ON UPDATE NULL);"), "t")
+	Convey("Should get an error for an ON UPDATE without a value", t, func() {
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "ON UPDATE")
+	})
+}
+
+func TestDescribeMysqlDDL(t *testing.T) {
+	table, err := DescribeMysqlDDL(strings.NewReader(testDDL), "users")
+	Convey("Should describe the columns the way INFORMATION_SCHEMA does", t, func() {
+		So(err, ShouldBeNil)
+		So(table.Name, ShouldEqual, "users")
+		So(table.Column("id").ColumnType, ShouldEqual, "int(10) unsigned")
+		So(table.Column("id").Extra, ShouldEqual, "auto_increment")
+		So(table.Column("id").Default, ShouldBeNil)
+		So(table.Column("email").Length, ShouldEqual, 255)
+		So(table.Column("balance").Precision, ShouldEqual, 10)
+		So(table.Column("balance").Scale, ShouldEqual, 2)
+		So(*table.Column("balance").Default, ShouldEqual, "0.00")
+		So(table.Column("created_at").Extra, ShouldEqual, "on update current_timestamp")
+	})
+	Convey("Should describe the indexes the way INFORMATION_SCHEMA does", t, func() {
+		So(table.Indexes, ShouldResemble, []*Index{
+			{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true},
+			{Name: "users_email", Columns: []string{"email"}, Lengths: []int64{191}, Unique: true},
+			{Name: "users_name_active", Columns: []string{"name", "active"}},
+			{Name: "users_team", Columns: []string{"team_id"}},
+		})
+	})
+}
+
+func TestMysqlDDLBitAndHexDefaults(t *testing.T) {
+	ddl := "CREATE TABLE flags (enabled bit(1) NOT NULL DEFAULT b'0', mask binary(1) NOT NULL DEFAULT X'0F', " +
+		"bits bit(8) NOT NULL DEFAULT 0x1F)"
+	table, err := DescribeMysqlDDL(strings.NewReader(ddl), "flags")
+	Convey("Should keep the prefix and quotes of bit and hex literal defaults", t, func() {
+		So(err, ShouldBeNil)
+		So(*table.Column("enabled").Default, ShouldEqual, "b'0'")
+		So(*table.Column("mask").Default, ShouldEqual, "X'0F'")
+		So(*table.Column("bits").Default, ShouldEqual, "0x1F")
+		_, ok := table.Column("enabled").defaultLiteral(DialectMysql)
+		So(ok, ShouldBeFalse)
+	})
+
+	bytes, err := GenerateStruct(table, "", GenerateOptions{PackageName: "test", Tags: []string{TagGorm}})
+	Convey("Should generate the gorm default of bit literals", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldContainSubstring, "default:b'0'")
+	})
+}
+
+func TestMysqlDDLGeneratedColumns(t *testing.T) {
+	ddl := "CREATE TABLE items (price decimal(10,2) NOT NULL, qty int NOT NULL, " +
+		"total decimal(12,2) GENERATED ALWAYS AS (price * qty) STORED, label varchar(20) AS (concat('#', qty)), " +
+		"tax decimal(12,2) AS (total / 10) PERSISTENT)"
+	table, err := DescribeMysqlDDL(strings.NewReader(ddl), "items")
+	Convey("Should describe generated columns the way INFORMATION_SCHEMA does", t, func() {
+		So(err, ShouldBeNil)
+		So(table.Column("total").Extra, ShouldEqual, "STORED GENERATED")
+		So(table.Column("total").GenerationExpression, ShouldEqual, "price*qty")
+		So(table.Column("label").Extra, ShouldEqual, "VIRTUAL GENERATED")
+		So(table.Column("label").GenerationExpression, ShouldEqual, "concat('#',qty)")
+		So(table.Column("tax").Extra, ShouldEqual, "STORED GENERATED")
+		So(table.Column("qty").Extra, ShouldBeEmpty)
+	})
+}
+
+func TestGetColumnsFromMysqlDDLFile(t *testing.T) {
+	file, err := os.Open("tests/mariadb.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	columnMap, columnsSorted, err := GetColumnsFromMysqlDDL(file, "all_data_types")
+	Convey("Should be able to parse the test database schema", t, func() {
+		So(err, ShouldBeNil)
+		So(columnsSorted, ShouldHaveLength, 28)
+		So(columnsSorted[0], ShouldEqual, "varchar")
+		So((*columnMap)["float"]["value"], ShouldEqual, "float")
+		So((*columnMap)["bool"]["value"], ShouldEqual, "tinyint")
+		So((*columnMap)["set"]["value"], ShouldEqual, "set")
+		So((*columnMap)["varbinary"]["nullable"], ShouldEqual, "NO")
+	})
+	bytes, err := Generate(*columnMap, columnsSorted, "all_data_types", "allDataTypes", "test", false, false, false)
+	Convey("Should be able to generate a struct for every data type of the test database schema", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldContainSubstring, "Year       int16\n")
+		So(string(bytes), ShouldContainSubstring, "Bool       bool\n")
+		So(string(bytes), ShouldContainSubstring, "Enum       allDataTypesEnum\n")
+		So(string(bytes), ShouldContainSubstring, "Set        allDataTypesSet\n")
+		So(string(bytes), ShouldContainSubstring, "Tinyblob   []byte\n")
+	})
+}
+
+func TestMysqlDDLIndexStatements(t *testing.T) {
+	schema := newDDLSchema()
+	err := schema.execReader(strings.NewReader(`
+		CREATE TABLE posts (id INT PRIMARY KEY, slug VARCHAR(20), title VARCHAR(20));
+		CREATE UNIQUE INDEX posts_slug ON posts (slug);
+		CREATE INDEX posts_title ON posts (title(10));
+		DROP INDEX posts_title ON posts;
+		ALTER TABLE posts CHANGE slug permalink VARCHAR(40) NOT NULL, DROP PRIMARY KEY;`))
+	columnMap, _ := schema.table("posts").columnMap()
+	Convey("Should apply index statements", t, func() {
+		So(err, ShouldBeNil)
+		So(columnMap["id"]["primary"], ShouldEqual, "")
+		So(columnMap["id"]["nullable"], ShouldEqual, "NO")
+		So(columnMap["permalink"]["primary"], ShouldEqual, "UNI")
+		So(columnMap["title"]["primary"], ShouldEqual, "")
+	})
+
+	schema = newDDLSchema()
+	err = schema.execReader(strings.NewReader(`
+		CREATE TABLE pages (id INT PRIMARY KEY, slug VARCHAR(40), title VARCHAR(40));
+		CREATE INDEX pages_title_slug ON pages (title(12), slug);
+		ALTER TABLE pages CHANGE slug permalink VARCHAR(40), CHANGE title heading VARCHAR(40);`))
+	index := schema.table("pages").toTable().Indexes[0]
+	Convey("Should describe the prefix lengths of index columns", t, func() {
+		So(err, ShouldBeNil)
+		So(index.Name, ShouldEqual, "pages_title_slug")
+		So(index.Columns, ShouldResemble, []string{"heading", "permalink"})
+		So(index.Lengths, ShouldResemble, []int64{12, 0})
+		So(index.Length("heading"), ShouldEqual, 12)
+		So(index.Position("permalink"), ShouldEqual, 2)
+	})
+}
+
+func TestMysqlDDLForeignKeys(t *testing.T) {
+	ddl := `
+		CREATE TABLE squads (id INT PRIMARY KEY);
+		CREATE TABLE teams (id INT PRIMARY KEY, parent_id INT,
+			FOREIGN KEY (parent_id) REFERENCES teams (id) ON DELETE SET NULL ON UPDATE CASCADE);
+		CREATE TABLE members (team_id INT, user_id INT, PRIMARY KEY (team_id, user_id));
+		CREATE TABLE grants (id INT PRIMARY KEY, team_id INT, user_id INT, owner_id INT REFERENCES teams (id),
+			CONSTRAINT grants_member FOREIGN KEY (team_id, user_id) REFERENCES members (team_id, user_id) ON DELETE CASCADE);
+		ALTER TABLE grants ADD CONSTRAINT grants_squad FOREIGN KEY (team_id) REFERENCES squads (id),
+			ADD CONSTRAINT grants_owner FOREIGN KEY (owner_id) REFERENCES teams (id);
+		ALTER TABLE grants DROP FOREIGN KEY grants_owner;
+		RENAME TABLE squads TO crews;
+		ALTER TABLE teams CHANGE parent_id parent_team_id INT;`
+
+	table, err := DescribeMysqlDDL(strings.NewReader(ddl), "grants")
+	Convey("Should describe the foreign keys of a table, inline references are ignored", t, func() {
+		So(err, ShouldBeNil)
+		So(table.ForeignKeys, ShouldResemble, []*ForeignKey{
+			{Name: "grants_member", Table: "grants", Columns: []string{"team_id", "user_id"}, ReferencedTable: "members",
+				ReferencedColumns: []string{"team_id", "user_id"}, OnDelete: "CASCADE", OnUpdate: "NO ACTION"},
+			{Name: "grants_squad", Table: "grants", Columns: []string{"team_id"}, ReferencedTable: "crews",
+				ReferencedColumns: []string{"id"}, OnDelete: "NO ACTION", OnUpdate: "NO ACTION"},
+		})
+	})
+
+	table, err = DescribeMysqlDDL(strings.NewReader(ddl), "teams")
+	Convey("Should describe self referencing foreign keys and name unnamed foreign keys the way InnoDB does", t, func() {
+		So(err, ShouldBeNil)
+		So(table.ForeignKeys, ShouldResemble, []*ForeignKey{
+			{Name: "teams_ibfk_1", Table: "teams", Columns: []string{"parent_team_id"}, ReferencedTable: "teams",
+				ReferencedColumns: []string{"id"}, OnDelete: "SET NULL", OnUpdate: "CASCADE"},
+		})
+		So(table.ReferencedBy, ShouldResemble, table.ForeignKeys)
+	})
+
+	table, err = DescribeMysqlDDL(strings.NewReader(ddl), "crews")
+	Convey("Should describe the foreign keys referencing a renamed table", t, func() {
+		So(err, ShouldBeNil)
+		So(table.ReferencedBy, ShouldHaveLength, 1)
+		So(table.ReferencedBy[0].Name, ShouldEqual, "grants_squad")
+	})
+}
+
+func TestLexDDL(t *testing.T) {
+	tokens, err := lexDDL("`a``b` 'it''s' \"q\\\"s\" # comment\n12 /* block\n */ c")
+	Convey("Should unquote identifiers and strings and skip comments", t, func() {
+		So(err, ShouldBeNil)
+		So(tokens, ShouldResemble, []ddlToken{
+			{kind: ddlIdent, text: "a`b", line: 1},
+			{kind: ddlString, text: "it's", line: 1},
+			{kind: ddlString, text: `q"s`, line: 1},
+			{kind: ddlNumber, text: "12", line: 2},
+			{kind: ddlWord, text: "c", line: 3},
+		})
+	})
+
+	_, err = lexDDL("'unterminated")
+	Convey("Should get an error for an unterminated string", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
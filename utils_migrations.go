@@ -0,0 +1,129 @@
+package db2struct
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GetColumnsFromMigrations Replay the up migrations of a mysql migrations directory and return map of map for the table
+//
+// Migrations are applied in the order of their numeric version prefix. Both golang-migrate style files
+// (1_create_users.up.sql) and goose style files (00001_create_users.sql with -- +goose Up and -- +goose Down
+// sections) are supported, down migrations are skipped.
+func GetColumnsFromMigrations(migrationsDir string, mysqlTable string) (*map[string]map[string]string, []string, error) {
+	table, err := DescribeMigrations(migrationsDir, mysqlTable)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columnDataTypes, columnNamesSorted := table.columnMap()
+	return &columnDataTypes, columnNamesSorted, nil
+}
+
+// DescribeMigrations Replay the up migrations of a mysql migrations directory and describe the table
+func DescribeMigrations(migrationsDir string, mysqlTable string) (*Table, error) {
+	schema, err := replayMigrations(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	table := schema.table(mysqlTable)
+	if table == nil {
+		return nil, fmt.Errorf("table %s does not exist after applying the migrations", mysqlTable)
+	}
+	return schema.describe(table), nil
+}
+
+// migrationFile is an up migration in a migrations directory
+type migrationFile struct {
+	version uint64
+	name    string
+}
+
+// replayMigrations applies the up migrations of a directory to an empty schema
+func replayMigrations(migrationsDir string) (*ddlSchema, error) {
+	migrations, err := findMigrations(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := newDDLSchema()
+	for _, migration := range migrations {
+		if Debug {
+			fmt.Println("applying: " + migration.name)
+		}
+		content, err := ioutil.ReadFile(filepath.Join(migrationsDir, migration.name))
+		if err != nil {
+			return nil, err
+		}
+		if err = schema.execReader(strings.NewReader(migrationUpSQL(string(content)))); err != nil {
+			return nil, fmt.Errorf("error applying migration %s: %s", migration.name, err)
+		}
+	}
+	return schema, nil
+}
+
+// findMigrations returns the up migrations of a directory sorted by version
+func findMigrations(migrationsDir string) ([]migrationFile, error) {
+	files, err := ioutil.ReadDir(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []migrationFile
+	versions := make(map[uint64]string)
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, ".sql") || strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+		end := strings.IndexFunc(name, func(r rune) bool { return !unicode.IsDigit(r) })
+		if end <= 0 {
+			continue
+		}
+		version, err := strconv.ParseUint(name[:end], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of migration %s: %s", name, err)
+		}
+		if other, ok := versions[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s have the same version %d", other, name, version)
+		}
+		versions[version] = name
+		migrations = append(migrations, migrationFile{version: version, name: name})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+	return migrations, nil
+}
+
+// migrationUpSQL returns the up section of a goose migration, other migrations are returned unchanged
+func migrationUpSQL(content string) string {
+	if !strings.Contains(content, "+goose Up") {
+		return content
+	}
+
+	var up []string
+	inUp := false
+	for _, line := range strings.Split(content, "\n") {
+		annotation := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(annotation, "-- +goose Up"):
+			inUp = true
+		case strings.HasPrefix(annotation, "-- +goose Down"):
+			inUp = false
+		case inUp:
+			up = append(up, line)
+			continue
+		}
+		// keep the line numbers of the up section
+		up = append(up, "")
+	}
+	return strings.Join(up, "\n")
+}
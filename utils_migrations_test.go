@@ -0,0 +1,85 @@
+package db2struct
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// writeTestMigrations writes the migrations to a temporary directory and returns it
+func writeTestMigrations(t *testing.T, migrations map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range migrations {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGetColumnsFromMigrations(t *testing.T) {
+	dir := writeTestMigrations(t, map[string]string{
+		"1_create_users.up.sql":   "CREATE TABLE users (id INT NOT NULL AUTO_INCREMENT PRIMARY KEY, name VARCHAR(50), legacy INT);",
+		"1_create_users.down.sql": "DROP TABLE users;",
+		"2_alter_users.up.sql": `ALTER TABLE users
+			ADD COLUMN email VARCHAR(255) NOT NULL AFTER id,
+			ADD UNIQUE INDEX users_email (email),
+			DROP COLUMN legacy,
+			MODIFY name VARCHAR(100) NOT NULL COMMENT 'full name';`,
+		"2_alter_users.down.sql": "ALTER TABLE users DROP COLUMN email;",
+		"10_rename.sql": `-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users RENAME COLUMN name TO full_name, ADD created_at DATETIME FIRST;
+RENAME TABLE users TO accounts;
+CREATE TABLE scratch (id INT);
+DROP TABLE scratch;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE accounts;
+`,
+		"README.md": "not a migration",
+	})
+
+	columnMap, columnsSorted, err := GetColumnsFromMigrations(dir, "accounts")
+	Convey("Should be able to replay migrations in version order", t, func() {
+		So(err, ShouldBeNil)
+		So(columnsSorted, ShouldResemble, []string{"created_at", "id", "email", "full_name"})
+		So((*columnMap)["id"]["primary"], ShouldEqual, "PRI")
+		So((*columnMap)["email"]["primary"], ShouldEqual, "UNI")
+		So((*columnMap)["full_name"], ShouldResemble, map[string]string{"value": "varchar", "column_type": "varchar(100)", "nullable": "NO", "primary": "", "comment": "full name"})
+	})
+
+	_, _, err = GetColumnsFromMigrations(dir, "users")
+	Convey("Should get an error for a renamed table", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+
+	_, _, err = GetColumnsFromMigrations(dir, "scratch")
+	Convey("Should get an error for a dropped table", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestGetColumnsFromMigrationsErrors(t *testing.T) {
+	dir := writeTestMigrations(t, map[string]string{
+		"1_create.up.sql": "CREATE TABLE users (id INT);",
+		"2_alter.up.sql":  "ALTER TABLE users DROP COLUMN missing;",
+	})
+	_, _, err := GetColumnsFromMigrations(dir, "users")
+	Convey("Should get an error naming the failing migration", t, func() {
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "2_alter.up.sql")
+	})
+
+	dir = writeTestMigrations(t, map[string]string{
+		"001_create.up.sql": "CREATE TABLE users (id INT);",
+		"1_other.up.sql":    "CREATE TABLE others (id INT);",
+	})
+	_, _, err = GetColumnsFromMigrations(dir, "users")
+	Convey("Should get an error for duplicate versions", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,185 @@
+package db2struct
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MysqlClientConfig Returns the connection options of the mysql client
+//
+// The MYSQL_HOST, MYSQL_TCP_PORT, MYSQL_UNIX_PORT and MYSQL_PWD environment variables are overridden by the [client]
+// group of the defaults file, ~/.my.cnf if defaultsFile is empty and it exists. A socket is returned as a host of the
+// form unix:/path unless another host than localhost is set.
+func MysqlClientConfig(defaultsFile string) (ConnectionConfig, error) {
+	options := map[string]string{
+		"host":     os.Getenv("MYSQL_HOST"),
+		"port":     os.Getenv("MYSQL_TCP_PORT"),
+		"socket":   os.Getenv("MYSQL_UNIX_PORT"),
+		"password": os.Getenv("MYSQL_PWD"),
+	}
+
+	if defaultsFile == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			if _, err = os.Stat(filepath.Join(home, ".my.cnf")); err == nil {
+				defaultsFile = filepath.Join(home, ".my.cnf")
+			}
+		}
+	}
+	if defaultsFile != "" {
+		fileOptions, err := ReadMysqlOptionFile(defaultsFile, "client")
+		if err != nil {
+			return ConnectionConfig{}, err
+		}
+		for key, value := range fileOptions {
+			options[key] = value
+		}
+	}
+
+	config := ConnectionConfig{
+		User:     options["user"],
+		Password: options["password"],
+		Host:     options["host"],
+		Database: options["database"],
+	}
+	if options["port"] != "" {
+		port, err := strconv.Atoi(options["port"])
+		if err != nil {
+			return ConnectionConfig{}, fmt.Errorf("invalid port %q: %s", options["port"], err)
+		}
+		config.Port = port
+	}
+	if socket := options["socket"]; socket != "" && (config.Host == "" || config.Host == "localhost") {
+		config.Host = "unix:" + socket
+	}
+	return config, nil
+}
+
+// ReadMysqlOptionFile Reads the options of the groups of a mysql option file (https://dev.mysql.com/doc/refman/8.0/en/option-files.html)
+//
+// Options read later override the ones read earlier. Option names are returned in lower case with underscores
+// replaced by dashes, options without a value are returned with an empty value. The !include and !includedir
+// directives are followed.
+func ReadMysqlOptionFile(path string, groups ...string) (map[string]string, error) {
+	options := make(map[string]string)
+	if err := readMysqlOptionFile(path, groups, options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// readMysqlOptionFile reads the options of the groups of an option file into options
+func readMysqlOptionFile(path string, groups []string, options map[string]string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	inGroup := false
+	group := ""
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || text[0] == '#' || text[0] == ';':
+			continue
+		case strings.HasPrefix(text, "!includedir"):
+			if err = readMysqlOptionDir(strings.TrimSpace(strings.TrimPrefix(text, "!includedir")), groups, options); err != nil {
+				return err
+			}
+		case strings.HasPrefix(text, "!include"):
+			if err = readMysqlOptionFile(strings.TrimSpace(strings.TrimPrefix(text, "!include")), groups, options); err != nil {
+				return err
+			}
+		case text[0] == '[':
+			end := strings.IndexByte(text, ']')
+			if end < 0 {
+				return fmt.Errorf("%s:%d: invalid group %s", path, line, text)
+			}
+			group = strings.TrimSpace(text[1:end])
+			inGroup = false
+			for _, g := range groups {
+				inGroup = inGroup || strings.EqualFold(g, group)
+			}
+		default:
+			if group == "" {
+				return fmt.Errorf("%s:%d: option %s outside of a group", path, line, text)
+			}
+			name, value := text, ""
+			if i := strings.IndexByte(text, '='); i >= 0 {
+				name, value = strings.TrimSpace(text[:i]), unquoteMysqlOption(strings.TrimSpace(text[i+1:]))
+			}
+			if inGroup {
+				options[strings.Replace(strings.ToLower(name), "_", "-", -1)] = value
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// readMysqlOptionDir reads the .cnf option files of a directory in name order
+func readMysqlOptionDir(dir string, groups []string, options map[string]string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".cnf") {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err = readMysqlOptionFile(filepath.Join(dir, name), groups, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unquoteMysqlOption removes the quotes, trailing comment and escape sequences of an option value
+func unquoteMysqlOption(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+
+	var unquoted strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			unquoted.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'b':
+			unquoted.WriteByte('\b')
+		case 't':
+			unquoted.WriteByte('\t')
+		case 'n':
+			unquoted.WriteByte('\n')
+		case 'r':
+			unquoted.WriteByte('\r')
+		case 's':
+			unquoted.WriteByte(' ')
+		case '\\':
+			unquoted.WriteByte('\\')
+		default:
+			// mysql keeps unknown escape sequences, such as the separators of windows paths
+			unquoted.WriteByte('\\')
+			unquoted.WriteByte(value[i])
+		}
+	}
+	return unquoted.String()
+}
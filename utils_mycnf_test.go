@@ -0,0 +1,87 @@
+package db2struct
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testMysqlOptionFile = `
+# client options
+[client]
+user = app
+password = "p#ss word"
+host=db.example.com
+port=3307
+skip_secure_auth
+
+[mysql]
+database = ignored
+
+[Client]
+socket = /var/run/mysqld/mysqld.sock ; not a comment
+database = app\sdb # comment
+`
+
+func TestReadMysqlOptionFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "my.cnf")
+	if err := ioutil.WriteFile(path, []byte(testMysqlOptionFile+"!includedir "+filepath.Join(dir, "conf.d")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "conf.d"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "conf.d", "port.cnf"), []byte("[client]\nport=3308\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	options, err := ReadMysqlOptionFile(path, "client")
+	Convey("Should read the options of the client group", t, func() {
+		So(err, ShouldBeNil)
+		So(options["user"], ShouldEqual, "app")
+		So(options["password"], ShouldEqual, "p#ss word")
+		So(options["host"], ShouldEqual, "db.example.com")
+		So(options["port"], ShouldEqual, "3308")
+		So(options["skip-secure-auth"], ShouldEqual, "")
+		So(options["socket"], ShouldEqual, "/var/run/mysqld/mysqld.sock ; not a comment")
+		So(options["database"], ShouldEqual, "app db")
+	})
+
+	_, err = ReadMysqlOptionFile(filepath.Join(dir, "missing.cnf"), "client")
+	Convey("Should get an error for a missing file", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestMysqlClientConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "my.cnf")
+	if err := ioutil.WriteFile(path, []byte("[client]\nuser=app\nsocket=/tmp/mysql.sock\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MYSQL_HOST", "")
+	t.Setenv("MYSQL_TCP_PORT", "3307")
+	t.Setenv("MYSQL_PWD", "secret")
+
+	config, err := MysqlClientConfig(path)
+	Convey("Should merge the defaults file over the environment", t, func() {
+		So(err, ShouldBeNil)
+		So(config, ShouldResemble, ConnectionConfig{User: "app", Password: "secret", Host: "unix:/tmp/mysql.sock", Port: 3307})
+	})
+
+	t.Setenv("MYSQL_TCP_PORT", "port")
+	_, err = MysqlClientConfig(path)
+	Convey("Should get an error for an invalid port", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestMysqlDSN(t *testing.T) {
+	Convey("Should build the same parameters for tcp and unix sockets", t, func() {
+		So(mysqlDSN("user", "p@ss", "localhost", 3306, "test"), ShouldEqual, "user:p@ss@tcp(localhost:3306)/test?parseTime=true")
+		So(mysqlDSN("user", "", "unix:/tmp/mysql.sock", 3306, "test"), ShouldEqual, "user@unix(/tmp/mysql.sock)/test?parseTime=true")
+	})
+}
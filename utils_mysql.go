@@ -1,140 +1,440 @@
 package db2struct
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 // GetColumnsFromMysqlTable Select column details from information schema and return map of map
 func GetColumnsFromMysqlTable(mariadbUser string, mariadbPassword string, mariadbHost string, mariadbPort int, mariadbDatabase string, mariadbTable string) (*map[string]map[string]string, []string, error) {
+	table, err := DescribeMysqlTable(mariadbUser, mariadbPassword, mariadbHost, mariadbPort, mariadbDatabase, mariadbTable)
+	if err != nil {
+		return nil, nil, err
+	}
+	columnDataTypes, columnNamesSorted := table.columnMap()
+	return &columnDataTypes, columnNamesSorted, nil
+}
+
+// GetColumnsFromMysqlDatabase Select column details of every table of a database from information schema over a single
+// connection and return map of map per table name
+func GetColumnsFromMysqlDatabase(mariadbUser string, mariadbPassword string, mariadbHost string, mariadbPort int, mariadbDatabase string) (map[string]map[string]map[string]string, map[string][]string, error) {
+	tables, err := DescribeMysqlDatabase(mariadbUser, mariadbPassword, mariadbHost, mariadbPort, mariadbDatabase)
+	if err != nil {
+		return nil, nil, err
+	}
+	tableColumnDataTypes := make(map[string]map[string]map[string]string)
+	tableColumnNamesSorted := make(map[string][]string)
+	for _, table := range tables {
+		tableColumnDataTypes[table.Name], tableColumnNamesSorted[table.Name] = table.columnMap()
+	}
+	return tableColumnDataTypes, tableColumnNamesSorted, nil
+}
+
+// DescribeMysqlTable Select column details of a table from information schema
+//
+// A table which does not exist is an error.
+func DescribeMysqlTable(mariadbUser string, mariadbPassword string, mariadbHost string, mariadbPort int, mariadbDatabase string, mariadbTable string) (*Table, error) {
+
+	db, err := openMysql(mariadbUser, mariadbPassword, mariadbHost, mariadbPort, mariadbDatabase)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return DescribeMysqlTableContext(context.Background(), db, mariadbDatabase, mariadbTable)
+}
+
+// DescribeMysqlDatabase Select column details of every table of a database from information schema over a single
+// connection, tables are returned in name order
+func DescribeMysqlDatabase(mariadbUser string, mariadbPassword string, mariadbHost string, mariadbPort int, mariadbDatabase string) ([]*Table, error) {
+
+	db, err := openMysql(mariadbUser, mariadbPassword, mariadbHost, mariadbPort, mariadbDatabase)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return DescribeMysqlDatabaseContext(context.Background(), db, mariadbDatabase)
+}
+
+// mysqlIntrospector is the Introspector of the mysql driver
+type mysqlIntrospector struct {
+	db       *sql.DB
+	database string
+}
+
+func init() {
+	RegisterIntrospector(DialectMysql, newMysqlIntrospector)
+}
+
+// newMysqlIntrospector opens a mysql database for introspection, the DSN is used as go-sql-driver/mysql DSN if given
+func newMysqlIntrospector(config ConnectionConfig) (Introspector, error) {
+	if config.DSN == "" {
+		config.DSN = mysqlDSN(config.User, config.Password, config.Host, config.Port, config.Database)
+	}
+	dsn, err := mysql.ParseDSN(config.DSN)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("mysql", config.DSN)
+	if err != nil {
+		return nil, err
+	}
+	return &mysqlIntrospector{db: db, database: dsn.DBName}, nil
+}
+
+// ListTables calls ListTablesContext with a background context
+func (i *mysqlIntrospector) ListTables() ([]string, error) {
+	return i.ListTablesContext(context.Background())
+}
 
-	var err error
-	var db *sql.DB
+// ListTablesContext returns the names of the tables and views of the database in name order
+func (i *mysqlIntrospector) ListTablesContext(ctx context.Context) ([]string, error) {
+	return getMysqlTables(ctx, i.db, i.database)
+}
+
+// DescribeTable calls DescribeTableContext with a background context
+func (i *mysqlIntrospector) DescribeTable(table string) (*Table, error) {
+	return i.DescribeTableContext(context.Background(), table)
+}
+
+// DescribeTableContext describes a table of the database, a table which does not exist is an error
+func (i *mysqlIntrospector) DescribeTableContext(ctx context.Context, table string) (*Table, error) {
+	return DescribeMysqlTableContext(ctx, i.db, i.database, table)
+}
+
+// DescribeSchema calls DescribeSchemaContext with a background context
+func (i *mysqlIntrospector) DescribeSchema() ([]*Table, error) {
+	return i.DescribeSchemaContext(context.Background())
+}
+
+// DescribeSchemaContext describes every table of the database in name order
+func (i *mysqlIntrospector) DescribeSchemaContext(ctx context.Context) ([]*Table, error) {
+	return DescribeMysqlDatabaseContext(ctx, i.db, i.database)
+}
+
+// SampleJSONColumnsContext sets the Samples of the json columns of the table to at most limit non-null values
+func (i *mysqlIntrospector) SampleJSONColumnsContext(ctx context.Context, table *Table, limit int) error {
+	return SampleJSONColumnsContext(ctx, i.db, table, limit)
+}
+
+// Close closes the database
+func (i *mysqlIntrospector) Close() error {
+	return i.db.Close()
+}
+
+// openMysql opens a mysql database, a host of the form unix:/path connects through the socket /path
+func openMysql(mariadbUser string, mariadbPassword string, mariadbHost string, mariadbPort int, mariadbDatabase string) (*sql.DB, error) {
+
+	db, err := sql.Open("mysql", mysqlDSN(mariadbUser, mariadbPassword, mariadbHost, mariadbPort, mariadbDatabase))
+
+	// Check for error in db, note this does not check connectivity but does check uri
+	if err != nil {
+		fmt.Println("Error opening mysql db: " + err.Error())
+		return nil, err
+	}
+	return db, nil
+}
+
+// mysqlDSN builds a go-sql-driver/mysql DSN, a host of the form unix:/path connects through the socket /path
+func mysqlDSN(mariadbUser string, mariadbPassword string, mariadbHost string, mariadbPort int, mariadbDatabase string) string {
+	config := mysql.NewConfig()
+	config.User = mariadbUser
+	config.Passwd = mariadbPassword
+	config.DBName = mariadbDatabase
+	config.ParseTime = true
 	if strings.HasPrefix(mariadbHost, "unix:") {
-		parts := strings.SplitN(mariadbHost, ":", 2)
-		socketPath := parts[1]
-		db, err = sql.Open("mysql", mariadbUser+":"+mariadbPassword+"@unix("+socketPath+")"+"/"+mariadbDatabase+"?charset=utf8&parseTime=True")
 		// Cite: https://dev.mysql.com/doc/mysql-shell/8.0/en/mysql-shell-connection-socket.html
-		// Cite: https://stackoverflow.com/a/67867865/71978
+		config.Net = "unix"
+		config.Addr = strings.SplitN(mariadbHost, ":", 2)[1]
 	} else {
-		if mariadbPassword != "" {
-			db, err = sql.Open("mysql", mariadbUser+":"+mariadbPassword+"@tcp("+mariadbHost+":"+strconv.Itoa(mariadbPort)+")/"+mariadbDatabase+"?&parseTime=True")
-		} else {
-			db, err = sql.Open("mysql", mariadbUser+"@tcp("+mariadbHost+":"+strconv.Itoa(mariadbPort)+")/"+mariadbDatabase+"?&parseTime=True")
+		config.Net = "tcp"
+		config.Addr = net.JoinHostPort(mariadbHost, strconv.Itoa(mariadbPort))
+	}
+	return config.FormatDSN()
+}
+
+// DescribeMysqlTableContext Select column details of a table from information schema with the queryer, such as an
+// existing *sql.DB, and a context for cancellation. The current database of the connection is used if mariadbDatabase
+// is empty. A table which does not exist is an error.
+func DescribeMysqlTableContext(ctx context.Context, q Queryer, mariadbDatabase string, mariadbTable string) (*Table, error) {
+	tables, err := describeMysqlTables(ctx, q, mariadbDatabase, mariadbTable)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("table %s does not exist", mariadbTable)
+	}
+	return tables[0], nil
+}
+
+// DescribeMysqlDatabaseContext Select column details of every table of a database from information schema with the
+// queryer and a context for cancellation. The current database of the connection is used if mariadbDatabase is empty.
+func DescribeMysqlDatabaseContext(ctx context.Context, q Queryer, mariadbDatabase string) ([]*Table, error) {
+	names, err := getMysqlTables(ctx, q, mariadbDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	described, err := describeMysqlTables(ctx, q, mariadbDatabase, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Every table listed in INFORMATION_SCHEMA.TABLES is returned, even if it has no columns
+	tables := make([]*Table, 0, len(names))
+	for _, name := range names {
+		table := &Table{Name: name, Schema: mariadbDatabase, Dialect: DialectMysql}
+		for _, t := range described {
+			if t.Name == name {
+				table = t
+			}
 		}
+		tables = append(tables, table)
 	}
-	defer db.Close()
+	return tables, nil
+}
 
-	// Check for error in db, note this does not check connectivity but does check uri
+// getMysqlTables Select the names of the tables and views of a database from information schema
+func getMysqlTables(ctx context.Context, q Queryer, mariadbDatabase string) ([]string, error) {
+	tableQuery := "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) order by table_name asc"
+
+	if Debug {
+		fmt.Println("running: " + tableQuery)
+	}
+
+	rows, err := q.QueryContext(ctx, tableQuery, mariadbDatabase)
 	if err != nil {
-		fmt.Println("Error opening mysql db: " + err.Error())
-		return nil, nil, err
+		fmt.Println("Error selecting from db: " + err.Error())
+		return nil, err
 	}
+	defer rows.Close()
 
-	columnNamesSorted := []string{}
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// describeMysqlTables Select column details of a table, or of all tables if mariadbTable is empty, from information
+// schema. Tables are returned in name order, tables without columns are not returned.
+func describeMysqlTables(ctx context.Context, q Queryer, mariadbDatabase string, mariadbTable string) ([]*Table, error) {
+
+	var tables []*Table
 
-	// Store colum as map of maps
-	columnDataTypes := make(map[string]map[string]string)
 	// Select columnd data from INFORMATION_SCHEMA
-	columnDataTypeQuery := "SELECT COLUMN_NAME, COLUMN_KEY, DATA_TYPE, IS_NULLABLE, COLUMN_COMMENT FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND table_name = ? order by ordinal_position asc"
+	columnDataTypeQuery := "SELECT TABLE_NAME, COLUMN_NAME, COLUMN_KEY, DATA_TYPE, COLUMN_TYPE, IS_NULLABLE, " +
+		"CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE, COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT, " +
+		"COALESCE(GENERATION_EXPRESSION, '') " +
+		"FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())"
+	args := []interface{}{mariadbDatabase}
+	if mariadbTable != "" {
+		columnDataTypeQuery += " AND table_name = ?"
+		args = append(args, mariadbTable)
+	}
+	columnDataTypeQuery += " order by table_name asc, ordinal_position asc"
 
 	if Debug {
 		fmt.Println("running: " + columnDataTypeQuery)
 	}
 
-	rows, err := db.Query(columnDataTypeQuery, mariadbDatabase, mariadbTable)
+	rows, err := q.QueryContext(ctx, columnDataTypeQuery, args...)
 
 	if err != nil {
 		fmt.Println("Error selecting from db: " + err.Error())
-		return nil, nil, err
+		return nil, err
 	}
 	if rows != nil {
 		defer rows.Close()
 	} else {
-		return nil, nil, errors.New("No results returned for table")
+		return nil, errors.New("No results returned for table")
 	}
 
 	for rows.Next() {
-		var column string
-		var columnKey string
-		var dataType string
+		var tableName string
+		var column Column
 		var nullable string
-		var comment string
-		rows.Scan(&column, &columnKey, &dataType, &nullable, &comment)
+		var length, precision, scale sql.NullInt64
+		var defaultValue sql.NullString
+		if err = rows.Scan(&tableName, &column.Name, &column.Key, &column.DataType, &column.ColumnType, &nullable,
+			&length, &precision, &scale, &defaultValue, &column.Extra, &column.Comment, &column.GenerationExpression); err != nil {
+			return nil, err
+		}
+		column.Nullable = nullable == "YES"
+		column.Length = length.Int64
+		column.Precision = precision.Int64
+		column.Scale = scale.Int64
+		if defaultValue.Valid {
+			column.Default = &defaultValue.String
+		}
 
-		columnDataTypes[column] = map[string]string{"value": dataType, "nullable": nullable, "primary": columnKey, "comment": comment}
-		columnNamesSorted = append(columnNamesSorted, column)
+		if len(tables) == 0 || tables[len(tables)-1].Name != tableName {
+			tables = append(tables, &Table{Name: tableName, Schema: mariadbDatabase, Dialect: DialectMysql})
+		}
+		table := tables[len(tables)-1]
+		table.Columns = append(table.Columns, &column)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
+	// the rows are closed before selecting the indexes, as a single connection can not run both queries at once
+	rows.Close()
 
-	return &columnDataTypes, columnNamesSorted, err
+	if err = describeMysqlIndexes(ctx, q, mariadbDatabase, mariadbTable, tables); err != nil {
+		return nil, err
+	}
+	if err = describeMysqlForeignKeys(ctx, q, mariadbDatabase, mariadbTable, tables); err != nil {
+		return nil, err
+	}
+	return tables, nil
 }
 
-// Generate go struct entries for a map[string]interface{} structure
-func generateMysqlTypes(obj map[string]map[string]string, columnsSorted []string, depth int, jsonAnnotation bool, gormAnnotation bool, gureguTypes bool) string {
-	structure := "struct {"
+// describeMysqlIndexes Select the indexes of a table, or of all tables if mariadbTable is empty, from information
+// schema and adds them to the tables
+func describeMysqlIndexes(ctx context.Context, q Queryer, mariadbDatabase string, mariadbTable string, tables []*Table) error {
+	indexQuery := "SELECT TABLE_NAME, INDEX_NAME, COLUMN_NAME, SUB_PART, NON_UNIQUE FROM INFORMATION_SCHEMA.STATISTICS " +
+		"WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())"
+	args := []interface{}{mariadbDatabase}
+	if mariadbTable != "" {
+		indexQuery += " AND TABLE_NAME = ?"
+		args = append(args, mariadbTable)
+	}
+	indexQuery += " ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX"
 
-	for _, key := range columnsSorted {
-		mysqlType := obj[key]
-		nullable := false
-		if mysqlType["nullable"] == "YES" {
-			nullable = true
-		}
+	if Debug {
+		fmt.Println("running: " + indexQuery)
+	}
 
-		primary := ""
-		if mysqlType["primary"] == "PRI" {
-			primary = ";primary_key"
+	rows, err := q.QueryContext(ctx, indexQuery, args...)
+	if err != nil {
+		fmt.Println("Error selecting from db: " + err.Error())
+		return err
+	}
+	defer rows.Close()
+
+	tablesByName := make(map[string]*Table, len(tables))
+	for _, table := range tables {
+		tablesByName[table.Name] = table
+	}
+	for rows.Next() {
+		var tableName, indexName string
+		var column sql.NullString
+		var subPart sql.NullInt64
+		var nonUnique int
+		if err = rows.Scan(&tableName, &indexName, &column, &subPart, &nonUnique); err != nil {
+			return err
+		}
+		table := tablesByName[tableName]
+		// functional key parts have no column
+		if table == nil || !column.Valid {
+			continue
 		}
+		if len(table.Indexes) == 0 || table.Indexes[len(table.Indexes)-1].Name != indexName {
+			table.Indexes = append(table.Indexes, &Index{Name: indexName, Unique: nonUnique == 0, Primary: indexName == "PRIMARY"})
+		}
+		table.Indexes[len(table.Indexes)-1].addColumn(column.String, subPart.Int64)
+	}
+	return rows.Err()
+}
+
+// describeMysqlForeignKeys Select the foreign keys of a table and the foreign keys referencing it, or of all tables if
+// mariadbTable is empty, from information schema and adds them to the tables
+func describeMysqlForeignKeys(ctx context.Context, q Queryer, mariadbDatabase string, mariadbTable string, tables []*Table) error {
+	foreignKeyQuery := "SELECT k.TABLE_NAME, k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_NAME, " +
+		"k.REFERENCED_COLUMN_NAME, r.UPDATE_RULE, r.DELETE_RULE FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE k " +
+		"JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS r ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA " +
+		"AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME AND r.TABLE_NAME = k.TABLE_NAME " +
+		"WHERE k.TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND k.REFERENCED_TABLE_NAME IS NOT NULL"
+	args := []interface{}{mariadbDatabase}
+	if mariadbTable != "" {
+		foreignKeyQuery += " AND (k.TABLE_NAME = ? OR k.REFERENCED_TABLE_NAME = ?)"
+		args = append(args, mariadbTable, mariadbTable)
+	}
+	foreignKeyQuery += " ORDER BY k.TABLE_NAME, k.CONSTRAINT_NAME, k.ORDINAL_POSITION"
 
-		// Get the corresponding go value type for this mysql type
-		var valueType string
-		// If the guregu (https://github.com/guregu/null) CLI option is passed use its types, otherwise use go's sql.NullX
+	if Debug {
+		fmt.Println("running: " + foreignKeyQuery)
+	}
 
-		valueType = mysqlTypeToGoType(mysqlType["value"], nullable, gureguTypes)
+	rows, err := q.QueryContext(ctx, foreignKeyQuery, args...)
+	if err != nil {
+		fmt.Println("Error selecting from db: " + err.Error())
+		return err
+	}
+	defer rows.Close()
 
-		fieldName := fmtFieldName(stringifyFirstChar(key))
-		var annotations []string
-		if gormAnnotation == true {
-			annotations = append(annotations, fmt.Sprintf("gorm:\"column:%s%s\"", key, primary))
+	var foreignKeys []*ForeignKey
+	for rows.Next() {
+		var tableName, constraintName, column, referencedTable, referencedColumn, updateRule, deleteRule string
+		if err = rows.Scan(&tableName, &constraintName, &column, &referencedTable, &referencedColumn, &updateRule, &deleteRule); err != nil {
+			return err
 		}
-		if jsonAnnotation == true {
-			annotations = append(annotations, fmt.Sprintf("json:\"%s\"", key))
-		}
-
-		if len(annotations) > 0 {
-			// add colulmn comment
-			comment := mysqlType["comment"]
-			structure += fmt.Sprintf("\n%s %s `%s`  //%s", fieldName, valueType, strings.Join(annotations, " "), comment)
-			//structure += fmt.Sprintf("\n%s %s `%s`", fieldName, valueType, strings.Join(annotations, " "))
-		} else {
-			structure += fmt.Sprintf("\n%s %s", fieldName, valueType)
+		if len(foreignKeys) == 0 || foreignKeys[len(foreignKeys)-1].Table != tableName || foreignKeys[len(foreignKeys)-1].Name != constraintName {
+			foreignKeys = append(foreignKeys, &ForeignKey{Name: constraintName, Table: tableName, ReferencedTable: referencedTable,
+				OnDelete: deleteRule, OnUpdate: updateRule})
 		}
+		foreignKey := foreignKeys[len(foreignKeys)-1]
+		foreignKey.Columns = append(foreignKey.Columns, column)
+		foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, referencedColumn)
+	}
+	if err = rows.Err(); err != nil {
+		return err
 	}
-	return structure
+	addForeignKeys(tables, foreignKeys)
+	return nil
 }
 
 // mysqlTypeToGoType converts the mysql types to go compatible sql.Nullable (https://golang.org/pkg/database/sql/) types
-func mysqlTypeToGoType(mysqlType string, nullable bool, gureguTypes bool) string {
-	switch mysqlType {
-	case "tinyint", "int", "smallint", "mediumint":
+//
+// All MySQL 8 and MariaDB data types are converted, bit columns and the spatial types, which are read in the internal
+// WKB format, are converted to []byte. An empty string is returned for unknown types. Integer columns are converted to
+// the go type of the same width using the column type, such as uint64 for bigint unsigned, and tinyint(1) columns are
+// converted to bool. Nullable integer columns are converted to sql.NullInt64 or null.Int, except nullable bigint
+// unsigned columns, which are converted to *uint64 so that values above math.MaxInt64 fit. Nullable date, datetime
+// and timestamp columns are converted to sql.NullTime or null.Time, time columns, which hold durations, to strings.
+func mysqlTypeToGoType(mysqlType string, columnType string, nullable bool, gureguTypes bool) string {
+	columnType = strings.ToLower(columnType)
+	unsigned := strings.Contains(columnType, "unsigned")
+	dataType := strings.ToLower(mysqlType)
+	if dataType == "tinyint" && strings.HasPrefix(columnType, "tinyint(1)") {
+		dataType = "bool"
+	}
+
+	switch dataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "year":
+		if nullable && dataType == "bigint" && unsigned {
+			// neither database/sql nor guregu have a null type holding every bigint unsigned value
+			return "*" + golangUint64
+		}
 		if nullable {
 			if gureguTypes {
 				return gureguNullInt
 			}
 			return sqlNullInt
 		}
-		return golangInt
-	case "bigint":
+		return mysqlIntegerType(dataType, unsigned)
+	case "bool", "boolean":
 		if nullable {
 			if gureguTypes {
-				return gureguNullInt
+				return gureguNullBool
 			}
-			return sqlNullInt
+			return sqlNullBool
 		}
-		return golangInt64
-	case "char", "enum", "varchar", "longtext", "mediumtext", "text", "tinytext", "json":
+		return golangBool
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set", "json",
+		"nchar", "nvarchar", "inet4", "inet6", "uuid":
 		if nullable {
 			if gureguTypes {
 				return gureguNullString
@@ -142,12 +442,24 @@ func mysqlTypeToGoType(mysqlType string, nullable bool, gureguTypes bool) string
 			return sqlNullString
 		}
 		return "string"
-	case "date", "datetime", "time", "timestamp":
-		if nullable && gureguTypes {
-			return gureguNullTime
+	case "date", "datetime", "timestamp":
+		if nullable {
+			if gureguTypes {
+				return gureguNullTime
+			}
+			return sqlNullTime
 		}
 		return golangTime
-	case "decimal", "double":
+	case "time":
+		// time columns are durations, which the driver does not parse into a time.Time
+		if nullable {
+			if gureguTypes {
+				return gureguNullString
+			}
+			return sqlNullString
+		}
+		return "string"
+	case "decimal", "numeric", "double", "real":
 		if nullable {
 			if gureguTypes {
 				return gureguNullFloat
@@ -163,8 +475,38 @@ func mysqlTypeToGoType(mysqlType string, nullable bool, gureguTypes bool) string
 			return sqlNullFloat
 		}
 		return golangFloat32
-	case "binary", "blob", "longblob", "mediumblob", "varbinary":
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob", "bit",
+		"geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon",
+		"geometrycollection", "geomcollection":
 		return golangByteArray
 	}
 	return ""
 }
+
+// mysqlIntegerType returns the go integer type with the width of a mysql integer type
+func mysqlIntegerType(mysqlType string, unsigned bool) string {
+	switch mysqlType {
+	case "tinyint":
+		if unsigned {
+			return golangUint8
+		}
+		return golangInt8
+	case "smallint":
+		if unsigned {
+			return golangUint16
+		}
+		return golangInt16
+	case "year":
+		return golangInt16
+	case "bigint":
+		if unsigned {
+			return golangUint64
+		}
+		return golangInt64
+	}
+	// mediumint and int
+	if unsigned {
+		return golangUint32
+	}
+	return golangInt32
+}
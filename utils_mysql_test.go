@@ -1,6 +1,8 @@
 package db2struct
 
 import (
+	"context"
+	"database/sql"
 	"testing"
 
 	_ "github.com/go-sql-driver/mysql" // Initialize mysql driver
@@ -15,16 +17,149 @@ const testMariadbDatabase = "test"
 
 func TestGetColumnsFromMysqlTable(t *testing.T) {
 	var testTable = "all_data_types"
-	columMap, err := GetColumnsFromMysqlTable(testMariadbUsername, testMariadbPassword, testMariadbHost, testMariadbPort, testMariadbDatabase, testTable)
+	columMap, _, err := GetColumnsFromMysqlTable(testMariadbUsername, testMariadbPassword, testMariadbHost, testMariadbPort, testMariadbDatabase, testTable)
 	Convey("Should be able to connect to test database and create columnMap", t, func() {
 		So(err, ShouldBeNil)
 		So(columMap, ShouldNotBeNil)
 		So(*columMap, ShouldNotBeEmpty)
 	})
 
-	columMap, err = GetColumnsFromMysqlTable(testMariadbUsername, testMariadbPassword, "doesnotexists", testMariadbPort, testMariadbDatabase, testTable)
+	columMap, _, err = GetColumnsFromMysqlTable(testMariadbUsername, testMariadbPassword, "doesnotexists", testMariadbPort, testMariadbDatabase, testTable)
 	Convey("Should get an error connecting to test database", t, func() {
 		So(err, ShouldNotBeNil)
 		So(columMap, ShouldBeNil)
 	})
 }
+
+func TestGetColumnsFromMysqlDatabase(t *testing.T) {
+	tableColumnMap, tableColumnsSorted, err := GetColumnsFromMysqlDatabase(testMariadbUsername, testMariadbPassword, testMariadbHost, testMariadbPort, testMariadbDatabase)
+	Convey("Should be able to connect to test database and create columnMap per table", t, func() {
+		So(err, ShouldBeNil)
+		So(tableColumnMap["all_data_types"], ShouldNotBeEmpty)
+		So(tableColumnsSorted["all_data_types"][0], ShouldEqual, "varchar")
+	})
+}
+
+func TestDescribeMysqlTable(t *testing.T) {
+	table, err := DescribeMysqlTable(testMariadbUsername, testMariadbPassword, testMariadbHost, testMariadbPort, testMariadbDatabase, "all_data_types")
+	Convey("Should be able to describe the columns of a table", t, func() {
+		So(err, ShouldBeNil)
+		So(table.Name, ShouldEqual, "all_data_types")
+		So(table.Dialect, ShouldEqual, DialectMysql)
+		So(table.Columns[0].Name, ShouldEqual, "varchar")
+		So(table.Column("varchar").DataType, ShouldEqual, "varchar")
+		So(table.Column("varchar").Length, ShouldEqual, 20)
+		So(table.Column("decimal").Precision, ShouldEqual, 10)
+		So(table.Column("decimal").Scale, ShouldEqual, 2)
+	})
+}
+
+func TestMysqlIntrospector(t *testing.T) {
+	introspector, err := NewIntrospector(DialectMysql, ConnectionConfig{User: testMariadbUsername, Password: testMariadbPassword, Host: testMariadbHost, Port: testMariadbPort, Database: testMariadbDatabase})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer introspector.Close()
+
+	tables, err := introspector.ListTables()
+	Convey("Should list the tables of the test database", t, func() {
+		So(err, ShouldBeNil)
+		So(tables, ShouldContain, "all_data_types")
+	})
+
+	table, err := introspector.DescribeTable("all_data_types")
+	Convey("Should describe a table of the test database", t, func() {
+		So(err, ShouldBeNil)
+		So(table.Columns[0].Name, ShouldEqual, "varchar")
+	})
+}
+
+func TestDescribeMysqlTableContext(t *testing.T) {
+	db, err := sql.Open("mysql", testMariadbUsername+"@tcp("+testMariadbHost+":3306)/"+testMariadbDatabase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table, err := DescribeMysqlTableContext(context.Background(), db, "", "all_data_types")
+	Convey("Should describe a table of the current database with an existing database", t, func() {
+		So(err, ShouldBeNil)
+		So(table.Columns[0].Name, ShouldEqual, "varchar")
+	})
+
+	_, err = DescribeMysqlTableContext(context.Background(), db, "", "missing_table")
+	Convey("Should get an error for a table which does not exist", t, func() {
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "table missing_table does not exist")
+	})
+
+	tables, err := DescribeMysqlDatabaseContext(context.Background(), db, testMariadbDatabase)
+	Convey("Should describe every table of a database with an existing database", t, func() {
+		So(err, ShouldBeNil)
+		So(tables, ShouldNotBeEmpty)
+	})
+
+	var _ ContextIntrospector = &mysqlIntrospector{}
+}
+
+func TestDescribeMysqlIndexes(t *testing.T) {
+	db, err := sql.Open("mysql", testMariadbUsername+"@tcp("+testMariadbHost+":3306)/"+testMariadbDatabase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec("CREATE TABLE index_test (id int NOT NULL, email varchar(255) NOT NULL, org_id int NOT NULL, " +
+		"name varchar(64), PRIMARY KEY (id), UNIQUE KEY index_test_email (email), KEY index_test_org_name (org_id, name(10)))"); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE index_test")
+
+	table, err := DescribeMysqlTableContext(context.Background(), db, "", "index_test")
+	Convey("Should describe the indexes of a table", t, func() {
+		So(err, ShouldBeNil)
+		So(table.Indexes, ShouldResemble, []*Index{
+			{Name: "index_test_email", Columns: []string{"email"}, Unique: true},
+			{Name: "index_test_org_name", Columns: []string{"org_id", "name"}, Lengths: []int64{0, 10}},
+			{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true},
+		})
+	})
+}
+
+func TestDescribeMysqlForeignKeys(t *testing.T) {
+	db, err := sql.Open("mysql", testMariadbUsername+"@tcp("+testMariadbHost+":3306)/"+testMariadbDatabase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec("CREATE TABLE foreign_key_parent (a int NOT NULL, b int NOT NULL, PRIMARY KEY (a, b))"); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE foreign_key_parent")
+	if _, err = db.Exec("CREATE TABLE foreign_key_child (id int NOT NULL, a int, b int, parent_id int, PRIMARY KEY (id), " +
+		"CONSTRAINT foreign_key_child_parent FOREIGN KEY (a, b) REFERENCES foreign_key_parent (a, b) ON DELETE CASCADE, " +
+		"CONSTRAINT foreign_key_child_self FOREIGN KEY (parent_id) REFERENCES foreign_key_child (id))"); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE foreign_key_child")
+
+	child, err := DescribeMysqlTableContext(context.Background(), db, "", "foreign_key_child")
+	Convey("Should describe the composite and self referencing foreign keys of a table", t, func() {
+		So(err, ShouldBeNil)
+		So(child.ForeignKeys, ShouldResemble, []*ForeignKey{
+			{Name: "foreign_key_child_parent", Table: "foreign_key_child", Columns: []string{"a", "b"}, ReferencedTable: "foreign_key_parent",
+				ReferencedColumns: []string{"a", "b"}, OnDelete: "CASCADE", OnUpdate: "NO ACTION"},
+			{Name: "foreign_key_child_self", Table: "foreign_key_child", Columns: []string{"parent_id"}, ReferencedTable: "foreign_key_child",
+				ReferencedColumns: []string{"id"}, OnDelete: "NO ACTION", OnUpdate: "NO ACTION"},
+		})
+		So(child.ReferencedBy, ShouldHaveLength, 1)
+		So(child.ReferencedBy[0].Name, ShouldEqual, "foreign_key_child_self")
+	})
+
+	parent, err := DescribeMysqlTableContext(context.Background(), db, "", "foreign_key_parent")
+	Convey("Should describe the foreign keys referencing a table", t, func() {
+		So(err, ShouldBeNil)
+		So(parent.ForeignKeys, ShouldBeEmpty)
+		So(parent.ReferencedBy, ShouldHaveLength, 1)
+		So(parent.ReferencedBy[0].Table, ShouldEqual, "foreign_key_child")
+	})
+}
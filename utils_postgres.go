@@ -0,0 +1,445 @@
+package db2struct
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetColumnsFromPostgresTable Select column details from information schema and pg_catalog and return map of map
+//
+// The table may be schema qualified (schema.table), otherwise the current schema of the connection is used.
+func GetColumnsFromPostgresTable(postgresUser string, postgresPassword string, postgresHost string, postgresPort int, postgresDatabase string, postgresTable string) (*map[string]map[string]string, []string, error) {
+	table, err := DescribePostgresTable(postgresUser, postgresPassword, postgresHost, postgresPort, postgresDatabase, postgresTable)
+	if err != nil {
+		return nil, nil, err
+	}
+	columnDataTypes, columnNamesSorted := table.columnMap()
+	return &columnDataTypes, columnNamesSorted, nil
+}
+
+// DescribePostgresTable Select column details of a table from information schema and pg_catalog
+//
+// The table may be schema qualified (schema.table), otherwise the current schema of the connection is used. The
+// DataType of the columns is the udt name, such as int4 or _text, and enum types are reported as enum. A table which
+// does not exist is an error.
+func DescribePostgresTable(postgresUser string, postgresPassword string, postgresHost string, postgresPort int, postgresDatabase string, postgresTable string) (*Table, error) {
+
+	db, err := sql.Open("postgres", postgresDSN(postgresUser, postgresPassword, postgresHost, postgresPort, postgresDatabase))
+
+	// Check for error in db, note this does not check connectivity but does check uri
+	if err != nil {
+		fmt.Println("Error opening postgres db: " + err.Error())
+		return nil, err
+	}
+	defer db.Close()
+
+	return DescribePostgresTableContext(context.Background(), db, postgresTable)
+}
+
+// postgresIntrospector is the Introspector of the postgres driver
+type postgresIntrospector struct {
+	db *sql.DB
+}
+
+func init() {
+	RegisterIntrospector(DialectPostgres, newPostgresIntrospector)
+}
+
+// newPostgresIntrospector opens a postgres database for introspection, the DSN is used as lib/pq connection string if given
+func newPostgresIntrospector(config ConnectionConfig) (Introspector, error) {
+	dsn := config.DSN
+	if dsn == "" {
+		dsn = postgresDSN(config.User, config.Password, config.Host, config.Port, config.Database)
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresIntrospector{db: db}, nil
+}
+
+// ListTables calls ListTablesContext with a background context
+func (i *postgresIntrospector) ListTables() ([]string, error) {
+	return i.ListTablesContext(context.Background())
+}
+
+// ListTablesContext returns the names of the tables and views of the current schema in name order
+func (i *postgresIntrospector) ListTablesContext(ctx context.Context) ([]string, error) {
+	return getPostgresTables(ctx, i.db)
+}
+
+// DescribeTable calls DescribeTableContext with a background context
+func (i *postgresIntrospector) DescribeTable(table string) (*Table, error) {
+	return i.DescribeTableContext(context.Background(), table)
+}
+
+// DescribeTableContext describes a table of the current schema, or a schema qualified (schema.table) table, a table
+// which does not exist is an error
+func (i *postgresIntrospector) DescribeTableContext(ctx context.Context, table string) (*Table, error) {
+	return DescribePostgresTableContext(ctx, i.db, table)
+}
+
+// DescribeSchema calls DescribeSchemaContext with a background context
+func (i *postgresIntrospector) DescribeSchema() ([]*Table, error) {
+	return i.DescribeSchemaContext(context.Background())
+}
+
+// DescribeSchemaContext describes every table of the current schema in name order
+func (i *postgresIntrospector) DescribeSchemaContext(ctx context.Context) ([]*Table, error) {
+	names, err := getPostgresTables(ctx, i.db)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]*Table, 0, len(names))
+	for _, name := range names {
+		table, err := describePostgresTable(ctx, i.db, name)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// SampleJSONColumnsContext sets the Samples of the json columns of the table to at most limit non-null values
+func (i *postgresIntrospector) SampleJSONColumnsContext(ctx context.Context, table *Table, limit int) error {
+	return SampleJSONColumnsContext(ctx, i.db, table, limit)
+}
+
+// Close closes the database
+func (i *postgresIntrospector) Close() error {
+	return i.db.Close()
+}
+
+// getPostgresTables Select the names of the tables and views of the current schema from information schema
+func getPostgresTables(ctx context.Context, q Queryer) ([]string, error) {
+	tableQuery := "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() ORDER BY table_name ASC"
+
+	if Debug {
+		fmt.Println("running: " + tableQuery)
+	}
+
+	rows, err := q.QueryContext(ctx, tableQuery)
+	if err != nil {
+		fmt.Println("Error selecting from db: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// DescribePostgresTableContext Select column details of a table from information schema and pg_catalog with the
+// queryer, such as an existing *sql.DB, and a context for cancellation. The table may be schema qualified
+// (schema.table), otherwise the current schema of the connection is used. A table which does not exist is an error.
+func DescribePostgresTableContext(ctx context.Context, q Queryer, postgresTable string) (*Table, error) {
+	table, err := describePostgresTable(ctx, q, postgresTable)
+	if err != nil {
+		return nil, err
+	}
+	if len(table.Columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist", postgresTable)
+	}
+	return table, nil
+}
+
+// describePostgresTable Select column details of a table, a table which does not exist is returned without columns,
+// as are tables created without columns
+func describePostgresTable(ctx context.Context, q Queryer, postgresTable string) (*Table, error) {
+	schema := ""
+	if parts := strings.SplitN(postgresTable, ".", 2); len(parts) == 2 {
+		schema, postgresTable = parts[0], parts[1]
+	}
+
+	table := &Table{Name: postgresTable, Schema: schema, Dialect: DialectPostgres}
+
+	// Select column data from INFORMATION_SCHEMA, keys and comments are only available from pg_catalog
+	columnDataTypeQuery := `SELECT c.column_name,
+	CASE
+		WHEN EXISTS (SELECT 1 FROM pg_catalog.pg_constraint con WHERE con.conrelid = cls.oid AND con.contype = 'p' AND a.attnum = ANY(con.conkey)) THEN 'PRI'
+		WHEN EXISTS (SELECT 1 FROM pg_catalog.pg_constraint con WHERE con.conrelid = cls.oid AND con.contype = 'u' AND con.conkey = ARRAY[a.attnum]) THEN 'UNI'
+		WHEN EXISTS (SELECT 1 FROM pg_catalog.pg_index i WHERE i.indrelid = cls.oid AND a.attnum = ANY(i.indkey)) THEN 'MUL'
+		ELSE ''
+	END,
+	CASE WHEN t.typtype = 'e' THEN 'enum' ELSE c.udt_name END,
+	pg_catalog.format_type(a.atttypid, a.atttypmod),
+	c.is_nullable,
+	c.character_maximum_length,
+	c.numeric_precision,
+	c.numeric_scale,
+	c.column_default,
+	COALESCE(d.description, ''),
+	CASE
+		WHEN c.is_identity = 'YES' THEN 'auto_increment'
+		WHEN c.is_generated = 'ALWAYS' THEN 'STORED GENERATED'
+		ELSE ''
+	END,
+	COALESCE(c.generation_expression, '')
+FROM information_schema.columns c
+JOIN pg_catalog.pg_namespace ns ON ns.nspname = c.table_schema
+JOIN pg_catalog.pg_class cls ON cls.relnamespace = ns.oid AND cls.relname = c.table_name
+JOIN pg_catalog.pg_attribute a ON a.attrelid = cls.oid AND a.attname = c.column_name
+JOIN pg_catalog.pg_type t ON t.oid = a.atttypid
+LEFT JOIN pg_catalog.pg_description d ON d.objoid = cls.oid AND d.objsubid = a.attnum
+WHERE c.table_schema = COALESCE(NULLIF($1::text, ''), current_schema()) AND c.table_name = $2
+ORDER BY c.ordinal_position ASC`
+
+	if Debug {
+		fmt.Println("running: " + columnDataTypeQuery)
+	}
+
+	rows, err := q.QueryContext(ctx, columnDataTypeQuery, schema, postgresTable)
+
+	if err != nil {
+		fmt.Println("Error selecting from db: " + err.Error())
+		return nil, err
+	}
+	if rows != nil {
+		defer rows.Close()
+	} else {
+		return nil, errors.New("No results returned for table")
+	}
+
+	for rows.Next() {
+		column := &Column{}
+		var nullable string
+		var length, precision, scale sql.NullInt64
+		var defaultValue sql.NullString
+		if err = rows.Scan(&column.Name, &column.Key, &column.DataType, &column.ColumnType, &nullable,
+			&length, &precision, &scale, &defaultValue, &column.Comment, &column.Extra, &column.GenerationExpression); err != nil {
+			return nil, err
+		}
+		column.Nullable = nullable == "YES"
+		column.Length = length.Int64
+		column.Precision = precision.Int64
+		column.Scale = scale.Int64
+		if defaultValue.Valid {
+			column.Default = &defaultValue.String
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	table.Indexes, err = getPostgresIndexes(ctx, q, schema, postgresTable)
+	if err != nil {
+		return nil, err
+	}
+	foreignKeys, err := getPostgresForeignKeys(ctx, q, schema, postgresTable)
+	if err != nil {
+		return nil, err
+	}
+	addForeignKeys([]*Table{table}, foreignKeys)
+	return table, nil
+}
+
+// getPostgresIndexes Select the indexes of a table from pg_catalog in name order, expressions are left out of the
+// columns of indexes
+func getPostgresIndexes(ctx context.Context, q Queryer, schema string, postgresTable string) ([]*Index, error) {
+	indexQuery := `SELECT i.relname, ix.indisunique, ix.indisprimary, a.attname
+FROM pg_catalog.pg_class t
+JOIN pg_catalog.pg_namespace ns ON ns.oid = t.relnamespace
+JOIN pg_catalog.pg_index ix ON ix.indrelid = t.oid
+JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord) ON true
+JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE ns.nspname = COALESCE(NULLIF($1::text, ''), current_schema()) AND t.relname = $2
+ORDER BY i.relname, k.ord`
+
+	if Debug {
+		fmt.Println("running: " + indexQuery)
+	}
+
+	rows, err := q.QueryContext(ctx, indexQuery, schema, postgresTable)
+	if err != nil {
+		fmt.Println("Error selecting from db: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []*Index
+	for rows.Next() {
+		var name, column string
+		var unique, primary bool
+		if err = rows.Scan(&name, &unique, &primary, &column); err != nil {
+			return nil, err
+		}
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			indexes = append(indexes, &Index{Name: name, Unique: unique, Primary: primary})
+		}
+		index := indexes[len(indexes)-1]
+		index.Columns = append(index.Columns, column)
+	}
+	return indexes, rows.Err()
+}
+
+// postgresReferentialActions maps the confupdtype and confdeltype codes of pg_constraint to referential actions
+var postgresReferentialActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// getPostgresForeignKeys Select the foreign keys of a table and the foreign keys referencing it from pg_catalog, in
+// table and constraint name order
+func getPostgresForeignKeys(ctx context.Context, q Queryer, schema string, postgresTable string) ([]*ForeignKey, error) {
+	foreignKeyQuery := `SELECT t.relname, con.conname, a.attname, rt.relname, ra.attname, con.confupdtype, con.confdeltype
+FROM pg_catalog.pg_constraint con
+JOIN pg_catalog.pg_namespace ns ON ns.oid = con.connamespace
+JOIN pg_catalog.pg_class t ON t.oid = con.conrelid
+JOIN pg_catalog.pg_class rt ON rt.oid = con.confrelid
+JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord) ON true
+JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+JOIN pg_catalog.pg_attribute ra ON ra.attrelid = rt.oid AND ra.attnum = k.refattnum
+WHERE con.contype = 'f' AND ns.nspname = COALESCE(NULLIF($1::text, ''), current_schema()) AND (t.relname = $2 OR rt.relname = $2)
+ORDER BY t.relname, con.conname, k.ord`
+
+	if Debug {
+		fmt.Println("running: " + foreignKeyQuery)
+	}
+
+	rows, err := q.QueryContext(ctx, foreignKeyQuery, schema, postgresTable)
+	if err != nil {
+		fmt.Println("Error selecting from db: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foreignKeys []*ForeignKey
+	for rows.Next() {
+		var tableName, name, column, referencedTable, referencedColumn, updateType, deleteType string
+		if err = rows.Scan(&tableName, &name, &column, &referencedTable, &referencedColumn, &updateType, &deleteType); err != nil {
+			return nil, err
+		}
+		if len(foreignKeys) == 0 || foreignKeys[len(foreignKeys)-1].Table != tableName || foreignKeys[len(foreignKeys)-1].Name != name {
+			foreignKeys = append(foreignKeys, &ForeignKey{Name: name, Table: tableName, ReferencedTable: referencedTable,
+				OnDelete: postgresReferentialActions[deleteType], OnUpdate: postgresReferentialActions[updateType]})
+		}
+		foreignKey := foreignKeys[len(foreignKeys)-1]
+		foreignKey.Columns = append(foreignKey.Columns, column)
+		foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, referencedColumn)
+	}
+	return foreignKeys, rows.Err()
+}
+
+// postgresDSN builds a lib/pq connection string, a host of the form unix:/path connects through the socket directory /path
+func postgresDSN(postgresUser string, postgresPassword string, postgresHost string, postgresPort int, postgresDatabase string) string {
+	if strings.HasPrefix(postgresHost, "unix:") {
+		postgresHost = strings.SplitN(postgresHost, ":", 2)[1]
+	}
+	params := []string{
+		"host=" + quotePostgresParam(postgresHost),
+		"port=" + strconv.Itoa(postgresPort),
+		"user=" + quotePostgresParam(postgresUser),
+		"dbname=" + quotePostgresParam(postgresDatabase),
+	}
+	if postgresPassword != "" {
+		params = append(params, "password="+quotePostgresParam(postgresPassword))
+	}
+	return strings.Join(params, " ")
+}
+
+// quotePostgresParam quotes a value for use in a key=value connection string
+func quotePostgresParam(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `'`, `\'`, -1)
+	return "'" + value + "'"
+}
+
+// postgresTypeToGoType converts the postgres udt names to go compatible sql.Nullable (https://golang.org/pkg/database/sql/) types
+//
+// Array types (udt names starting with an underscore) are converted to the lib/pq array types.
+func postgresTypeToGoType(postgresType string, nullable bool, gureguTypes bool) string {
+	if strings.HasPrefix(postgresType, "_") {
+		switch postgresType[1:] {
+		case "int2", "int4", "int8":
+			return pqInt64Array
+		case "float4", "float8", "numeric":
+			return pqFloat64Array
+		case "bool":
+			return pqBoolArray
+		case "bytea":
+			return pqByteaArray
+		}
+		return pqStringArray
+	}
+
+	switch postgresType {
+	case "int2", "int4":
+		if nullable {
+			if gureguTypes {
+				return gureguNullInt
+			}
+			return sqlNullInt
+		}
+		return golangInt
+	case "int8":
+		if nullable {
+			if gureguTypes {
+				return gureguNullInt
+			}
+			return sqlNullInt
+		}
+		return golangInt64
+	case "bool":
+		if nullable {
+			if gureguTypes {
+				return gureguNullBool
+			}
+			return sqlNullBool
+		}
+		return golangBool
+	case "bpchar", "char", "varchar", "text", "citext", "name", "enum", "uuid", "json", "jsonb", "xml",
+		"inet", "cidr", "macaddr", "macaddr8", "interval", "money", "bit", "varbit", "tsvector", "tsquery":
+		if nullable {
+			if gureguTypes {
+				return gureguNullString
+			}
+			return sqlNullString
+		}
+		return "string"
+	case "date", "time", "timetz", "timestamp", "timestamptz":
+		if nullable {
+			if gureguTypes {
+				return gureguNullTime
+			}
+			return sqlNullTime
+		}
+		return golangTime
+	case "numeric", "float8":
+		if nullable {
+			if gureguTypes {
+				return gureguNullFloat
+			}
+			return sqlNullFloat
+		}
+		return golangFloat64
+	case "float4":
+		if nullable {
+			if gureguTypes {
+				return gureguNullFloat
+			}
+			return sqlNullFloat
+		}
+		return golangFloat32
+	case "bytea":
+		return golangByteArray
+	}
+	return ""
+}
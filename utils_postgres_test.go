@@ -0,0 +1,84 @@
+package db2struct
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPostgresGenerate(t *testing.T) {
+	expectedStruct :=
+		`package test
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type testStruct struct {
+	BigInt      int64
+	Bytes       []byte
+	Flag        sql.NullBool
+	ID          string
+	Inet        sql.NullString
+	Numeric     float64
+	Payload     string
+	Tags        pq.StringArray
+	Timestamptz time.Time
+	Totals      pq.Float64Array
+	UpdatedAt   sql.NullTime
+}
+`
+
+	columnMap := map[string]map[string]string{
+		"id":          {"nullable": "NO", "value": "uuid", "dialect": "postgres"},
+		"big_int":     {"nullable": "NO", "value": "int8", "dialect": "postgres"},
+		"bytes":       {"nullable": "YES", "value": "bytea", "dialect": "postgres"},
+		"flag":        {"nullable": "YES", "value": "bool", "dialect": "postgres"},
+		"inet":        {"nullable": "YES", "value": "inet", "dialect": "postgres"},
+		"numeric":     {"nullable": "NO", "value": "numeric", "dialect": "postgres"},
+		"payload":     {"nullable": "NO", "value": "jsonb", "dialect": "postgres"},
+		"tags":        {"nullable": "YES", "value": "_text", "dialect": "postgres"},
+		"timestamptz": {"nullable": "NO", "value": "timestamptz", "dialect": "postgres"},
+		"totals":      {"nullable": "NO", "value": "_numeric", "dialect": "postgres"},
+		"updated_at":  {"nullable": "YES", "value": "timestamptz", "dialect": "postgres"},
+	}
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, false)
+
+	Convey("Should be able to generate map from postgres columns", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+}
+
+func TestPostgresTypeToGureguType(t *testing.T) {
+	Convey("Should convert nullable postgres types to guregu null types", t, func() {
+		So(postgresTypeToGoType("int4", true, true), ShouldEqual, gureguNullInt)
+		So(postgresTypeToGoType("bool", true, true), ShouldEqual, gureguNullBool)
+		So(postgresTypeToGoType("varchar", true, true), ShouldEqual, gureguNullString)
+		So(postgresTypeToGoType("timestamptz", true, true), ShouldEqual, gureguNullTime)
+		So(postgresTypeToGoType("float4", true, true), ShouldEqual, gureguNullFloat)
+	})
+
+	Convey("Should convert nullable postgres temporal types to sql.NullTime", t, func() {
+		So(postgresTypeToGoType("timestamptz", true, false), ShouldEqual, sqlNullTime)
+		So(postgresTypeToGoType("date", true, false), ShouldEqual, sqlNullTime)
+		So(postgresTypeToGoType("date", false, false), ShouldEqual, golangTime)
+	})
+
+	Convey("Should not convert unknown postgres types", t, func() {
+		So(postgresTypeToGoType("tsrange", false, false), ShouldEqual, "")
+	})
+}
+
+func TestPostgresDSN(t *testing.T) {
+	Convey("Should build a tcp connection string", t, func() {
+		So(postgresDSN("user", "pa'ss", "localhost", 5432, "test"), ShouldEqual, `host='localhost' port=5432 user='user' dbname='test' password='pa\'ss'`)
+	})
+
+	Convey("Should build a unix socket connection string", t, func() {
+		So(postgresDSN("user", "", "unix:/var/run/postgresql", 5432, "test"), ShouldEqual, `host='/var/run/postgresql' port=5432 user='user' dbname='test'`)
+	})
+}
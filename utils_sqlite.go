@@ -0,0 +1,438 @@
+package db2struct
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetColumnsFromSqliteTable Select column details from the table_info, index_list and foreign_key_list pragmas and return map of map
+//
+// Keys are reported the way mysql reports them: PRI for primary key columns, UNI for columns with a single column
+// unique index and MUL for the first column of any other index or of a foreign key.
+func GetColumnsFromSqliteTable(sqliteDSN string, sqliteTable string) (*map[string]map[string]string, []string, error) {
+	table, err := DescribeSqliteTable(sqliteDSN, sqliteTable)
+	if err != nil {
+		return nil, nil, err
+	}
+	columnDataTypes, columnNamesSorted := table.columnMap()
+	return &columnDataTypes, columnNamesSorted, nil
+}
+
+// DescribeSqliteTable Select column details of a table from the table_info, index_list and foreign_key_list pragmas
+//
+// The DataType and ColumnType of the columns are the declared type, such as VARCHAR(255).
+func DescribeSqliteTable(sqliteDSN string, sqliteTable string) (*Table, error) {
+
+	db, err := sql.Open("sqlite3", sqliteReadOnlyDSN(sqliteDSN))
+
+	// Check for error in db, note this does not check connectivity but does check uri
+	if err != nil {
+		fmt.Println("Error opening sqlite db: " + err.Error())
+		return nil, err
+	}
+	defer db.Close()
+
+	return DescribeSqliteTableContext(context.Background(), db, sqliteTable)
+}
+
+// sqliteIntrospector is the Introspector of the sqlite driver
+type sqliteIntrospector struct {
+	db *sql.DB
+}
+
+func init() {
+	RegisterIntrospector(DialectSqlite, newSqliteIntrospector)
+}
+
+// newSqliteIntrospector opens the sqlite database of the DSN for introspection
+func newSqliteIntrospector(config ConnectionConfig) (Introspector, error) {
+	db, err := sql.Open("sqlite3", sqliteReadOnlyDSN(config.DSN))
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteIntrospector{db: db}, nil
+}
+
+// sqliteReadOnlyDSN returns the DSN of a database file opened read only, so that a mistyped path is an error instead of
+// a new empty database. DSNs with a mode and in-memory databases are returned as they are.
+func sqliteReadOnlyDSN(dsn string) string {
+	if strings.Contains(dsn, "mode=") || strings.Contains(dsn, ":memory:") {
+		return dsn
+	}
+	if !strings.HasPrefix(dsn, "file:") {
+		dsn = "file:" + dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&mode=ro"
+	}
+	return dsn + "?mode=ro"
+}
+
+// ListTables calls ListTablesContext with a background context
+func (i *sqliteIntrospector) ListTables() ([]string, error) {
+	return i.ListTablesContext(context.Background())
+}
+
+// ListTablesContext returns the names of the tables and views of the database in name order
+func (i *sqliteIntrospector) ListTablesContext(ctx context.Context) ([]string, error) {
+	return getSqliteTables(ctx, i.db)
+}
+
+// DescribeTable calls DescribeTableContext with a background context
+func (i *sqliteIntrospector) DescribeTable(table string) (*Table, error) {
+	return i.DescribeTableContext(context.Background(), table)
+}
+
+// DescribeTableContext describes a table of the database
+func (i *sqliteIntrospector) DescribeTableContext(ctx context.Context, table string) (*Table, error) {
+	return DescribeSqliteTableContext(ctx, i.db, table)
+}
+
+// DescribeSchema calls DescribeSchemaContext with a background context
+func (i *sqliteIntrospector) DescribeSchema() ([]*Table, error) {
+	return i.DescribeSchemaContext(context.Background())
+}
+
+// DescribeSchemaContext describes every table of the database in name order
+func (i *sqliteIntrospector) DescribeSchemaContext(ctx context.Context) ([]*Table, error) {
+	names, err := getSqliteTables(ctx, i.db)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]*Table, 0, len(names))
+	for _, name := range names {
+		table, err := DescribeSqliteTableContext(ctx, i.db, name)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// SampleJSONColumnsContext sets the Samples of the json columns of the table to at most limit non-null values
+func (i *sqliteIntrospector) SampleJSONColumnsContext(ctx context.Context, table *Table, limit int) error {
+	return SampleJSONColumnsContext(ctx, i.db, table, limit)
+}
+
+// Close closes the database
+func (i *sqliteIntrospector) Close() error {
+	return i.db.Close()
+}
+
+// getSqliteTables Select the names of the tables and views of the database from the schema table, internal sqlite
+// tables are skipped
+func getSqliteTables(ctx context.Context, q Queryer) ([]string, error) {
+	tableQuery := `SELECT name FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite\_%' ESCAPE '\' ORDER BY name ASC`
+
+	if Debug {
+		fmt.Println("running: " + tableQuery)
+	}
+
+	rows, err := q.QueryContext(ctx, tableQuery)
+	if err != nil {
+		fmt.Println("Error selecting from db: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// DescribeSqliteTableContext Select column details of a table from the table_info, index_list and foreign_key_list
+// pragmas with the queryer, such as an existing *sql.DB, and a context for cancellation
+func DescribeSqliteTableContext(ctx context.Context, q Queryer, sqliteTable string) (*Table, error) {
+	keys, indexes, err := getSqliteColumnKeys(ctx, q, sqliteTable)
+	if err != nil {
+		fmt.Println("Error selecting from db: " + err.Error())
+		return nil, err
+	}
+
+	table := &Table{Name: sqliteTable, Dialect: DialectSqlite, Indexes: indexes}
+	// table_xinfo also returns generated columns, hidden columns of virtual tables are left out as by table_info
+	columnDataTypeQuery := `SELECT name, type, "notnull", dflt_value, pk, hidden FROM pragma_table_xinfo(?) WHERE hidden <> 1 ORDER BY cid ASC`
+
+	if Debug {
+		fmt.Println("running: " + columnDataTypeQuery)
+	}
+
+	rows, err := q.QueryContext(ctx, columnDataTypeQuery, sqliteTable)
+
+	if err != nil {
+		fmt.Println("Error selecting from db: " + err.Error())
+		return nil, err
+	}
+	if rows != nil {
+		defer rows.Close()
+	} else {
+		return nil, errors.New("No results returned for table")
+	}
+
+	for rows.Next() {
+		var notNull bool
+		var defaultValue sql.NullString
+		var primaryKey, hidden int
+		column := &Column{}
+		if err = rows.Scan(&column.Name, &column.DataType, &notNull, &defaultValue, &primaryKey, &hidden); err != nil {
+			return nil, err
+		}
+		switch hidden {
+		case 2:
+			column.Extra = "VIRTUAL GENERATED"
+		case 3:
+			column.Extra = "STORED GENERATED"
+		}
+
+		// An INTEGER PRIMARY KEY is an alias for the rowid and can never be NULL
+		column.Nullable = !notNull && !(primaryKey > 0 && strings.EqualFold(column.DataType, "INTEGER"))
+		column.ColumnType = column.DataType
+		column.Key = keys[column.Name]
+		if defaultValue.Valid {
+			column.Default = &defaultValue.String
+		}
+		declaredType := strings.ToUpper(column.DataType)
+		column.setTypeSizes(!strings.Contains(declaredType, "CHAR") && !strings.Contains(declaredType, "CLOB") &&
+			!strings.Contains(declaredType, "TEXT") && !strings.Contains(declaredType, "BLOB"))
+		table.Columns = append(table.Columns, column)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if len(table.Columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist", sqliteTable)
+	}
+
+	foreignKeys, err := getSqliteForeignKeys(ctx, q, sqliteTable)
+	if err != nil {
+		return nil, err
+	}
+	addForeignKeys([]*Table{table}, foreignKeys)
+	return table, nil
+}
+
+// getSqliteForeignKeys returns the foreign keys of a table and the foreign keys of every table referencing it from the
+// foreign_key_list pragma, in table and name order. Foreign keys are unnamed in sqlite, they are named
+// table_columns_fkey the way postgres names them. Foreign keys without referenced columns reference the primary key.
+func getSqliteForeignKeys(ctx context.Context, q Queryer, sqliteTable string) ([]*ForeignKey, error) {
+	foreignKeyQuery := `SELECT m.name, fk.id, fk."from", fk."table",
+	COALESCE(fk."to", (SELECT name FROM pragma_table_info(fk."table") WHERE pk = fk.seq + 1)), fk.on_update, fk.on_delete
+FROM sqlite_master m, pragma_foreign_key_list(m.name) fk
+WHERE m.type = 'table' AND (m.name = ?1 OR fk."table" = ?1 COLLATE NOCASE)
+ORDER BY m.name, fk.id, fk.seq`
+
+	if Debug {
+		fmt.Println("running: " + foreignKeyQuery)
+	}
+
+	rows, err := q.QueryContext(ctx, foreignKeyQuery, sqliteTable)
+	if err != nil {
+		fmt.Println("Error selecting from db: " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foreignKeys []*ForeignKey
+	lastID := -1
+	for rows.Next() {
+		var tableName, column, referencedTable, onUpdate, onDelete string
+		var referencedColumn sql.NullString
+		var id int
+		if err = rows.Scan(&tableName, &id, &column, &referencedTable, &referencedColumn, &onUpdate, &onDelete); err != nil {
+			return nil, err
+		}
+		if len(foreignKeys) == 0 || foreignKeys[len(foreignKeys)-1].Table != tableName || id != lastID {
+			foreignKeys = append(foreignKeys, &ForeignKey{Table: tableName, ReferencedTable: referencedTable,
+				OnDelete: onDelete, OnUpdate: onUpdate})
+			lastID = id
+		}
+		foreignKey := foreignKeys[len(foreignKeys)-1]
+		foreignKey.Columns = append(foreignKey.Columns, column)
+		foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, referencedColumn.String)
+	}
+	for _, foreignKey := range foreignKeys {
+		foreignKey.Name = foreignKey.Table + "_" + strings.Join(foreignKey.Columns, "_") + "_fkey"
+	}
+	sort.Slice(foreignKeys, func(i, j int) bool {
+		if foreignKeys[i].Table != foreignKeys[j].Table {
+			return foreignKeys[i].Table < foreignKeys[j].Table
+		}
+		return foreignKeys[i].Name < foreignKeys[j].Name
+	})
+	return foreignKeys, rows.Err()
+}
+
+// getSqliteColumnKeys returns the PRI, UNI or MUL key of every indexed column of a table and the indexes of the
+// table in name order. The primary key is returned as the PRIMARY index.
+func getSqliteColumnKeys(ctx context.Context, q Queryer, sqliteTable string) (map[string]string, []*Index, error) {
+	keys := make(map[string]string)
+	var indexes []*Index
+
+	primaryRows, err := q.QueryContext(ctx, `SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk`, sqliteTable)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer primaryRows.Close()
+	primary := &Index{Name: "PRIMARY", Unique: true, Primary: true}
+	for primaryRows.Next() {
+		var column string
+		if err = primaryRows.Scan(&column); err != nil {
+			return nil, nil, err
+		}
+		keys[column] = KeyPrimary
+		primary.Columns = append(primary.Columns, column)
+	}
+	if err = primaryRows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(primary.Columns) > 0 {
+		indexes = append(indexes, primary)
+	}
+
+	indexRows, err := q.QueryContext(ctx, `SELECT il.name, il."unique", il.origin, ii.seqno, ii.name FROM pragma_index_list(?) il, pragma_index_info(il.name) ii ORDER BY il.name, ii.seqno`, sqliteTable)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer indexRows.Close()
+	indexColumns := make(map[string][]string)
+	uniqueIndexes := make(map[string]bool)
+	indexNames := []string{}
+	for indexRows.Next() {
+		var index, origin string
+		var unique bool
+		var seq int
+		var column sql.NullString
+		if err = indexRows.Scan(&index, &unique, &origin, &seq, &column); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := indexColumns[index]; !ok {
+			indexNames = append(indexNames, index)
+			// the index of a primary key which is not the rowid is already returned as PRIMARY
+			if origin != "pk" {
+				indexes = append(indexes, &Index{Name: index, Unique: unique})
+			}
+		}
+		// expression indexes have no column name
+		indexColumns[index] = append(indexColumns[index], column.String)
+		uniqueIndexes[index] = unique
+		if origin != "pk" && column.Valid {
+			indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, column.String)
+		}
+	}
+	if err = indexRows.Err(); err != nil {
+		return nil, nil, err
+	}
+	withColumns := indexes[:0]
+	for _, index := range indexes {
+		if len(index.Columns) > 0 {
+			withColumns = append(withColumns, index)
+		}
+	}
+	indexes = withColumns
+	for _, index := range indexNames {
+		columns := indexColumns[index]
+		if len(columns) == 1 && uniqueIndexes[index] && columns[0] != "" && keys[columns[0]] == "" {
+			keys[columns[0]] = KeyUnique
+		}
+	}
+	for _, index := range indexNames {
+		if column := indexColumns[index][0]; column != "" && keys[column] == "" {
+			keys[column] = KeyMultiple
+		}
+	}
+
+	// Foreign key columns are keys in mysql as InnoDB indexes them automatically
+	foreignRows, err := q.QueryContext(ctx, `SELECT "from" FROM pragma_foreign_key_list(?) WHERE seq = 0`, sqliteTable)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer foreignRows.Close()
+	for foreignRows.Next() {
+		var column string
+		if err = foreignRows.Scan(&column); err != nil {
+			return nil, nil, err
+		}
+		if keys[column] == "" {
+			keys[column] = KeyMultiple
+		}
+	}
+
+	return keys, indexes, foreignRows.Err()
+}
+
+// sqliteTypeToGoType converts the declared sqlite column types to go compatible sql.Nullable (https://golang.org/pkg/database/sql/) types
+//
+// The conversion follows the sqlite type affinity rules (https://www.sqlite.org/datatype3.html#determination_of_column_affinity),
+// columns with NUMERIC affinity declared as a boolean or a date are converted to bool and time.Time.
+func sqliteTypeToGoType(sqliteType string, nullable bool, gureguTypes bool) string {
+	declaredType := strings.ToUpper(sqliteType)
+	if i := strings.Index(declaredType, "("); i >= 0 {
+		declaredType = declaredType[:i]
+	}
+	declaredType = strings.TrimSpace(declaredType)
+
+	switch {
+	case strings.Contains(declaredType, "INT"):
+		if nullable {
+			if gureguTypes {
+				return gureguNullInt
+			}
+			return sqlNullInt
+		}
+		return golangInt64
+	case strings.Contains(declaredType, "CHAR"), strings.Contains(declaredType, "CLOB"), strings.Contains(declaredType, "TEXT"):
+		if nullable {
+			if gureguTypes {
+				return gureguNullString
+			}
+			return sqlNullString
+		}
+		return "string"
+	case declaredType == "", strings.Contains(declaredType, "BLOB"):
+		return golangByteArray
+	case strings.Contains(declaredType, "REAL"), strings.Contains(declaredType, "FLOA"), strings.Contains(declaredType, "DOUB"):
+		if nullable {
+			if gureguTypes {
+				return gureguNullFloat
+			}
+			return sqlNullFloat
+		}
+		return golangFloat64
+	case declaredType == "BOOL", declaredType == "BOOLEAN":
+		if nullable {
+			if gureguTypes {
+				return gureguNullBool
+			}
+			return sqlNullBool
+		}
+		return golangBool
+	case declaredType == "DATE", declaredType == "DATETIME", declaredType == "TIMESTAMP":
+		if nullable {
+			if gureguTypes {
+				return gureguNullTime
+			}
+			return sqlNullTime
+		}
+		return golangTime
+	}
+	// Remaining columns have NUMERIC affinity
+	if nullable {
+		if gureguTypes {
+			return gureguNullFloat
+		}
+		return sqlNullFloat
+	}
+	return golangFloat64
+}
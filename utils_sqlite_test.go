@@ -0,0 +1,268 @@
+package db2struct
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3" // Initialize sqlite driver
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testSqliteSchema = `
+CREATE TABLE users (
+	id INTEGER PRIMARY KEY,
+	email VARCHAR(255) NOT NULL UNIQUE,
+	name TEXT,
+	score REAL NOT NULL,
+	active BOOLEAN NOT NULL,
+	balance DECIMAL(10, 2),
+	avatar BLOB,
+	created_at DATETIME NOT NULL,
+	deleted_at DATETIME
+);
+CREATE TABLE posts (
+	id INTEGER PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id),
+	title TEXT NOT NULL,
+	slug TEXT NOT NULL
+);
+CREATE INDEX posts_title_slug ON posts(title, slug);
+`
+
+// newTestSqliteDatabase creates a sqlite database with the test schema and returns its dsn
+func newTestSqliteDatabase(t *testing.T) string {
+	dsn := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec(testSqliteSchema); err != nil {
+		t.Fatal(err)
+	}
+	return dsn
+}
+
+func TestGetColumnsFromSqliteTable(t *testing.T) {
+	dsn := newTestSqliteDatabase(t)
+
+	columnMap, columnsSorted, err := GetColumnsFromSqliteTable(dsn, "users")
+	Convey("Should be able to read the columns of a sqlite table", t, func() {
+		So(err, ShouldBeNil)
+		So(columnsSorted, ShouldResemble, []string{"id", "email", "name", "score", "active", "balance", "avatar", "created_at", "deleted_at"})
+		So((*columnMap)["id"]["primary"], ShouldEqual, "PRI")
+		So((*columnMap)["id"]["nullable"], ShouldEqual, "NO")
+		So((*columnMap)["email"]["primary"], ShouldEqual, "UNI")
+		So((*columnMap)["email"]["value"], ShouldEqual, "VARCHAR(255)")
+		So((*columnMap)["name"]["nullable"], ShouldEqual, "YES")
+	})
+
+	columnMap, _, err = GetColumnsFromSqliteTable(dsn, "posts")
+	Convey("Should report indexed and foreign key columns as keys", t, func() {
+		So(err, ShouldBeNil)
+		So((*columnMap)["user_id"]["primary"], ShouldEqual, "MUL")
+		So((*columnMap)["title"]["primary"], ShouldEqual, "MUL")
+		So((*columnMap)["slug"]["primary"], ShouldEqual, "")
+	})
+}
+
+func TestDescribeSqliteTable(t *testing.T) {
+	table, err := DescribeSqliteTable(newTestSqliteDatabase(t), "users")
+	Convey("Should describe the declared types of a sqlite table", t, func() {
+		So(err, ShouldBeNil)
+		So(table.Dialect, ShouldEqual, DialectSqlite)
+		So(table.Column("email").ColumnType, ShouldEqual, "VARCHAR(255)")
+		So(table.Column("email").Length, ShouldEqual, 255)
+		So(table.Column("balance").Precision, ShouldEqual, 10)
+		So(table.Column("balance").Scale, ShouldEqual, 2)
+		So(table.Column("id").Nullable, ShouldBeFalse)
+	})
+
+	dsn := newTestSqliteDatabase(t)
+	_, err = DescribeSqliteTable(dsn, "missing")
+	Convey("Should get an error for a table which does not exist", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+
+	missing := filepath.Join(t.TempDir(), "missing.db")
+	_, err = DescribeSqliteTable(missing, "users")
+	_, statErr := os.Stat(missing)
+	Convey("Should get an error for a database file which does not exist without creating it", t, func() {
+		So(err, ShouldNotBeNil)
+		So(os.IsNotExist(statErr), ShouldBeTrue)
+	})
+
+	Convey("Should open database files read only", t, func() {
+		So(sqliteReadOnlyDSN("test.db"), ShouldEqual, "file:test.db?mode=ro")
+		So(sqliteReadOnlyDSN("file:test.db?cache=shared"), ShouldEqual, "file:test.db?cache=shared&mode=ro")
+		So(sqliteReadOnlyDSN("file:test.db?mode=rw"), ShouldEqual, "file:test.db?mode=rw")
+		So(sqliteReadOnlyDSN(":memory:"), ShouldEqual, ":memory:")
+	})
+}
+
+func TestSqliteGenerate(t *testing.T) {
+	expectedStruct :=
+		`package test
+
+import (
+	"database/sql"
+	"time"
+)
+
+type testStruct struct {
+	ID        int64
+	Email     string
+	Name      sql.NullString
+	Score     float64
+	Active    bool
+	Balance   sql.NullFloat64
+	Avatar    []byte
+	CreatedAt time.Time
+	DeletedAt sql.NullTime
+}
+`
+
+	columnMap, columnsSorted, err := GetColumnsFromSqliteTable(newTestSqliteDatabase(t), "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bytes, err := Generate(*columnMap, columnsSorted, "users", "testStruct", "test", false, false, false)
+
+	Convey("Should be able to generate map from sqlite columns", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+}
+
+func TestSqliteTypeAffinity(t *testing.T) {
+	Convey("Should convert declared types by their affinity", t, func() {
+		So(sqliteTypeToGoType("DATETIME", true, false), ShouldEqual, sqlNullTime)
+		So(sqliteTypeToGoType("DATETIME", true, true), ShouldEqual, gureguNullTime)
+		So(sqliteTypeToGoType("UNSIGNED BIG INT", false, false), ShouldEqual, golangInt64)
+		So(sqliteTypeToGoType("NVARCHAR(100)", false, false), ShouldEqual, "string")
+		So(sqliteTypeToGoType("CLOB", true, true), ShouldEqual, gureguNullString)
+		So(sqliteTypeToGoType("", true, false), ShouldEqual, golangByteArray)
+		So(sqliteTypeToGoType("DOUBLE PRECISION", true, false), ShouldEqual, sqlNullFloat)
+		So(sqliteTypeToGoType("NUMERIC", false, false), ShouldEqual, golangFloat64)
+		So(sqliteTypeToGoType("boolean", true, true), ShouldEqual, gureguNullBool)
+		So(sqliteTypeToGoType("timestamp", true, true), ShouldEqual, gureguNullTime)
+	})
+}
+
+func TestSqliteIntrospector(t *testing.T) {
+	introspector, err := NewIntrospector(DialectSqlite, ConnectionConfig{DSN: newTestSqliteDatabase(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer introspector.Close()
+
+	tables, err := introspector.ListTables()
+	Convey("Should list the tables of a sqlite database", t, func() {
+		So(err, ShouldBeNil)
+		So(tables, ShouldResemble, []string{"posts", "users"})
+	})
+
+	described, err := introspector.DescribeSchema()
+	Convey("Should describe every table of a sqlite database", t, func() {
+		So(err, ShouldBeNil)
+		So(described, ShouldHaveLength, 2)
+		So(described[0].Name, ShouldEqual, "posts")
+		So(described[0].Column("user_id").Key, ShouldEqual, KeyMultiple)
+		So(described[0].Indexes, ShouldResemble, []*Index{
+			{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true},
+			{Name: "posts_title_slug", Columns: []string{"title", "slug"}},
+		})
+		So(described[1].Indexes[1].Columns, ShouldResemble, []string{"email"})
+		So(described[1].Indexes[1].Unique, ShouldBeTrue)
+		So(described[1].Columns, ShouldHaveLength, 9)
+	})
+	Convey("Should describe the foreign keys of a sqlite table and the foreign keys referencing it", t, func() {
+		So(described[0].ForeignKeys, ShouldResemble, []*ForeignKey{
+			{Name: "posts_user_id_fkey", Table: "posts", Columns: []string{"user_id"}, ReferencedTable: "users",
+				ReferencedColumns: []string{"id"}, OnDelete: "NO ACTION", OnUpdate: "NO ACTION"},
+		})
+		So(described[0].ReferencedBy, ShouldBeEmpty)
+		So(described[1].ForeignKeys, ShouldBeEmpty)
+		So(described[1].ReferencedBy, ShouldResemble, described[0].ForeignKeys)
+	})
+}
+
+func TestSqliteForeignKeys(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec(`
+		CREATE TABLE parents (a INTEGER, b INTEGER, PRIMARY KEY (a, b));
+		CREATE TABLE children (
+			id INTEGER PRIMARY KEY,
+			a INTEGER,
+			b INTEGER,
+			parent_id INTEGER REFERENCES children ON DELETE SET NULL,
+			FOREIGN KEY (a, b) REFERENCES parents
+		);`); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := DescribeSqliteTableContext(context.Background(), db, "children")
+	Convey("Should describe composite and self referencing foreign keys referencing the primary key", t, func() {
+		So(err, ShouldBeNil)
+		So(table.ForeignKeys, ShouldHaveLength, 2)
+		So(table.ForeignKeys[0].ReferencedTable, ShouldEqual, "parents")
+		So(table.ForeignKeys[0].Columns, ShouldResemble, []string{"a", "b"})
+		So(table.ForeignKeys[0].ReferencedColumns, ShouldResemble, []string{"a", "b"})
+		So(table.ForeignKeys[1].Name, ShouldEqual, "children_parent_id_fkey")
+		So(table.ForeignKeys[1].ReferencedColumns, ShouldResemble, []string{"id"})
+		So(table.ForeignKeys[1].OnDelete, ShouldEqual, "SET NULL")
+		So(table.ReferencedBy, ShouldResemble, table.ForeignKeys[1:])
+	})
+}
+
+func TestSqliteGeneratedColumns(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec(`CREATE TABLE items (price REAL NOT NULL, qty INTEGER NOT NULL DEFAULT 1,
+	total REAL GENERATED ALWAYS AS (price * qty) STORED, label TEXT AS ('#' || qty))`); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := DescribeSqliteTableContext(context.Background(), db, "items")
+	Convey("Should describe the generated columns of a table", t, func() {
+		So(err, ShouldBeNil)
+		So(table.Columns, ShouldHaveLength, 4)
+		So(table.Column("total").Extra, ShouldEqual, "STORED GENERATED")
+		So(table.Column("label").Extra, ShouldEqual, "VIRTUAL GENERATED")
+		So(*table.Column("qty").Default, ShouldEqual, "1")
+	})
+}
+
+func TestDescribeSqliteTableContext(t *testing.T) {
+	db, err := sql.Open("sqlite3", newTestSqliteDatabase(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table, err := DescribeSqliteTableContext(context.Background(), db, "posts")
+	Convey("Should describe a table with an existing database", t, func() {
+		So(err, ShouldBeNil)
+		So(table.Columns, ShouldHaveLength, 4)
+		So(db.Ping(), ShouldBeNil)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = DescribeSqliteTableContext(ctx, db, "posts")
+	Convey("Should get an error for a canceled context", t, func() {
+		So(err, ShouldEqual, context.Canceled)
+	})
+
+	var _ ContextIntrospector = &sqliteIntrospector{}
+}
@@ -0,0 +1,65 @@
+package db2struct
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// FilterTables returns the tables matching any of the include patterns, or all tables if there are none, and none of
+// the exclude patterns
+//
+// Patterns are glob patterns as accepted by path.Match, a pattern wrapped in slashes such as /^user_\d+$/ is a
+// regular expression instead.
+func FilterTables(tables []string, include []string, exclude []string) ([]string, error) {
+	filtered := []string{}
+	for _, table := range tables {
+		included := len(include) == 0
+		for _, pattern := range include {
+			matched, err := matchTable(pattern, table)
+			if err != nil {
+				return nil, err
+			}
+			included = included || matched
+		}
+		for _, pattern := range exclude {
+			matched, err := matchTable(pattern, table)
+			if err != nil {
+				return nil, err
+			}
+			included = included && !matched
+		}
+		if included {
+			filtered = append(filtered, table)
+		}
+	}
+	return filtered, nil
+}
+
+// matchTable reports whether the table name matches the glob or /regular expression/ pattern
+func matchTable(pattern string, table string) (bool, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return false, fmt.Errorf("invalid table pattern %s: %s", pattern, err)
+		}
+		return re.MatchString(table), nil
+	}
+	matched, err := path.Match(pattern, table)
+	if err != nil {
+		return false, fmt.Errorf("invalid table pattern %s: %s", pattern, err)
+	}
+	return matched, nil
+}
+
+// StructNameFromTable derives the name of the struct generated for a table
+//
+// Example:
+//
+//	StructNameFromTable("user_accounts")
+//
+// Output: UserAccounts
+func StructNameFromTable(table string) string {
+	return fmtFieldName(stringifyFirstChar(table))
+}
@@ -0,0 +1,47 @@
+package db2struct
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterTables(t *testing.T) {
+	tables := []string{"orders", "order_items", "order_items_tmp", "users", "user_1", "user_2"}
+
+	filtered, err := FilterTables(tables, nil, nil)
+	Convey("Should keep all tables without patterns", t, func() {
+		So(err, ShouldBeNil)
+		So(filtered, ShouldResemble, tables)
+	})
+
+	filtered, err = FilterTables(tables, []string{"order*"}, []string{"*_tmp"})
+	Convey("Should include and exclude tables by glob", t, func() {
+		So(err, ShouldBeNil)
+		So(filtered, ShouldResemble, []string{"orders", "order_items"})
+	})
+
+	filtered, err = FilterTables(tables, []string{`/^user_\d+$/`, "orders"}, nil)
+	Convey("Should include tables by regular expression", t, func() {
+		So(err, ShouldBeNil)
+		So(filtered, ShouldResemble, []string{"orders", "user_1", "user_2"})
+	})
+
+	_, err = FilterTables(tables, []string{"/(/"}, nil)
+	Convey("Should get an error for an invalid regular expression", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+
+	_, err = FilterTables(tables, nil, []string{"[users"})
+	Convey("Should get an error for an invalid glob", t, func() {
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestStructNameFromTable(t *testing.T) {
+	Convey("Should derive struct names from table names", t, func() {
+		So(StructNameFromTable("user_accounts"), ShouldEqual, "UserAccounts")
+		So(StructNameFromTable("api_keys"), ShouldEqual, "APIKeys")
+		So(StructNameFromTable("2fa_codes"), ShouldEqual, "TwoFaCodes")
+	})
+}
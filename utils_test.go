@@ -1,6 +1,7 @@
 package db2struct
 
 import (
+	"sort"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -42,6 +43,8 @@ func TestMysqlStringGenerate(t *testing.T) {
 	expectedStruct :=
 		`package test
 
+import "database/sql"
+
 type testStruct struct {
 	NullStringColumn sql.NullString
 	StringColumn     string
@@ -52,7 +55,7 @@ type testStruct struct {
 		"stringColumn":     {"nullable": "NO", "value": "varchar"},
 		"nullStringColumn": {"nullable": "YES", "value": "varchar"},
 	}
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", false, false, false)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, false)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -90,7 +93,7 @@ type testStruct struct {
 		"varbinaryColumn":      {"nullable": "NO", "value": "varbinary"},
 		"nullVarbinaryColumn":  {"nullable": "YES", "value": "varbinary"},
 	}
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", false, false, false)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, false)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -113,19 +116,24 @@ func TestMysqlDateGenerate(t *testing.T) {
 	expectedStruct :=
 		`package test
 
+import (
+	"database/sql"
+	"time"
+)
+
 type testStruct struct {
 	DateColumn          time.Time
 	DateTimeColumn      time.Time
-	TimeColumn          time.Time
+	TimeColumn          string
 	TimeStampColumn     time.Time
-	NullDateColumn      time.Time
-	NullDateTimeColumn  time.Time
-	NullTimeColumn      time.Time
-	NullTimeStampColumn time.Time
+	NullDateColumn      sql.NullTime
+	NullDateTimeColumn  sql.NullTime
+	NullTimeColumn      sql.NullString
+	NullTimeStampColumn sql.NullTime
 }
 `
 
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", false, false, false)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, false)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -135,19 +143,25 @@ type testStruct struct {
 	expectedStruct =
 		`package test
 
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
 type testStruct struct {
 	DateColumn          time.Time
 	DateTimeColumn      time.Time
-	TimeColumn          time.Time
+	TimeColumn          string
 	TimeStampColumn     time.Time
 	NullDateColumn      null.Time
 	NullDateTimeColumn  null.Time
-	NullTimeColumn      null.Time
+	NullTimeColumn      null.String
 	NullTimeStampColumn null.Time
 }
 `
 
-	bytes, err = Generate(columnMap, "test_table", "testStruct", "test", false, false, true)
+	bytes, err = Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, true)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -169,6 +183,8 @@ func TestMysqlFloatGenerate(t *testing.T) {
 	expectedStruct :=
 		`package test
 
+import "database/sql"
+
 type testStruct struct {
 	DecimalColumn     float64
 	DoubleColumn      float64
@@ -179,7 +195,7 @@ type testStruct struct {
 }
 `
 
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", false, false, false)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, false)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -189,6 +205,8 @@ type testStruct struct {
 	expectedStruct =
 		`package test
 
+import "gopkg.in/guregu/null.v4"
+
 type testStruct struct {
 	DecimalColumn     float64
 	DoubleColumn      float64
@@ -199,7 +217,7 @@ type testStruct struct {
 }
 `
 
-	bytes, err = Generate(columnMap, "test_table", "testStruct", "test", false, false, true)
+	bytes, err = Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, true)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -224,21 +242,23 @@ func TestMysqlIntGenerate(t *testing.T) {
 	expectedStruct :=
 		`package test
 
+import "database/sql"
+
 type testStruct struct {
 	BigIntColumn        int64
-	IntColumn           int
-	MediumIntColumn     int
+	IntColumn           int32
+	MediumIntColumn     int32
 	NullBigIntColumn    sql.NullInt64
 	NullIntColumn       sql.NullInt64
 	NullMediumIntColumn sql.NullInt64
 	NullSmallIntColumn  sql.NullInt64
 	NullTinyIntColumn   sql.NullInt64
-	SmallIntColumn      int
-	TinyIntColumn       int
+	SmallIntColumn      int16
+	TinyIntColumn       int8
 }
 `
 
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", false, false, false)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, false)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -248,21 +268,23 @@ type testStruct struct {
 	expectedStruct =
 		`package test
 
+import "gopkg.in/guregu/null.v4"
+
 type testStruct struct {
 	BigIntColumn        int64
-	IntColumn           int
-	MediumIntColumn     int
+	IntColumn           int32
+	MediumIntColumn     int32
 	NullBigIntColumn    null.Int
 	NullIntColumn       null.Int
 	NullMediumIntColumn null.Int
 	NullSmallIntColumn  null.Int
 	NullTinyIntColumn   null.Int
-	SmallIntColumn      int
-	TinyIntColumn       int
+	SmallIntColumn      int16
+	TinyIntColumn       int8
 }
 `
 
-	bytes, err = Generate(columnMap, "test_table", "testStruct", "test", false, false, true)
+	bytes, err = Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, true)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -270,6 +292,45 @@ type testStruct struct {
 	})
 }
 
+func TestMysqlColumnTypeGenerate(t *testing.T) {
+	columnMap := map[string]map[string]string{
+		"id":        {"nullable": "NO", "value": "bigint", "column_type": "bigint(20) unsigned"},
+		"active":    {"nullable": "NO", "value": "tinyint", "column_type": "tinyint(1)"},
+		"verified":  {"nullable": "YES", "value": "tinyint", "column_type": "tinyint(1)"},
+		"flags":     {"nullable": "NO", "value": "tinyint", "column_type": "tinyint(3) unsigned"},
+		"port":      {"nullable": "NO", "value": "smallint", "column_type": "smallint(5) unsigned"},
+		"count":     {"nullable": "NO", "value": "int", "column_type": "int(10) unsigned zerofill"},
+		"views":     {"nullable": "NO", "value": "mediumint", "column_type": "mediumint(8) unsigned"},
+		"nullViews": {"nullable": "YES", "value": "mediumint", "column_type": "mediumint(8) unsigned"},
+		"parentID":  {"nullable": "YES", "value": "bigint", "column_type": "bigint(20) unsigned"},
+	}
+
+	expectedStruct :=
+		`package test
+
+import "database/sql"
+
+type testStruct struct {
+	Active    bool
+	Count     uint32
+	Flags     uint8
+	ID        uint64
+	NullViews sql.NullInt64
+	ParentID  *uint64
+	Port      uint16
+	Verified  sql.NullBool
+	Views     uint32
+}
+`
+
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, false)
+
+	Convey("Should be able to generate unsigned and boolean types from the column type", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+}
+
 func TestMysqlJSONStringGenerate(t *testing.T) {
 	columnMap := map[string]map[string]string{
 		"stringColumn":     {"nullable": "NO", "value": "varchar"},
@@ -279,13 +340,15 @@ func TestMysqlJSONStringGenerate(t *testing.T) {
 	expectedStruct :=
 		`package test
 
+import "database/sql"
+
 type testStruct struct {
 	NullStringColumn sql.NullString ` + "`json:\"nullStringColumn\"`" + `
 	StringColumn     string         ` + "`json:\"stringColumn\"`" + `
 }
 `
 
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", true, false, false)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", true, false, false)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -297,9 +360,11 @@ func TestMysqlGormStringGenerate(t *testing.T) {
 	expectedStruct :=
 		`package test
 
+import "database/sql"
+
 type testStruct struct {
 	NullStringColumn sql.NullString ` + "`gorm:\"column:nullStringColumn\"`" + `
-	StringColumn     string         ` + "`gorm:\"column:stringColumn\"`" + `
+	StringColumn     string         ` + "`gorm:\"column:stringColumn;not null\"`" + `
 }
 
 // TableName sets the insert table name for this struct type
@@ -312,7 +377,7 @@ func (t *testStruct) TableName() string {
 		"stringColumn":     {"nullable": "NO", "value": "varchar"},
 		"nullStringColumn": {"nullable": "YES", "value": "varchar"},
 	}
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", false, true, false)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, true, false)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -332,7 +397,7 @@ type testStruct struct {
 	columnMap := map[string]map[string]string{
 		"1stringColumn": {"nullable": "NO", "value": "varchar"},
 	}
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", false, false, false)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, false)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -352,7 +417,7 @@ type testStruct struct {
 	columnMap := map[string]map[string]string{
 		"string_Column": {"nullable": "NO", "value": "varchar"},
 	}
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", false, false, false)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, false)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -372,7 +437,7 @@ type testStruct struct {
 	columnMap := map[string]map[string]string{
 		"API": {"nullable": "NO", "value": "varchar"},
 	}
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", false, false, false)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, false)
 
 	Convey("Should be able to generate map from string column", t, func() {
 		So(err, ShouldBeNil)
@@ -380,11 +445,43 @@ type testStruct struct {
 	})
 }
 
+func TestGenerateTables(t *testing.T) {
+	expectedStruct :=
+		`package test
+
+type Posts struct {
+	ID    int32  ` + "`json:\"id\"`" + `
+	Title string ` + "`json:\"title\"`" + `
+}
+
+type Users struct {
+	ID int32 ` + "`json:\"id\"`" + `
+}
+`
+
+	tableColumnMap := map[string]map[string]map[string]string{
+		"users": {"id": {"nullable": "NO", "value": "int"}},
+		"posts": {"id": {"nullable": "NO", "value": "int"}, "title": {"nullable": "NO", "value": "varchar"}},
+	}
+	tableColumnsSorted := map[string][]string{
+		"users": {"id"},
+		"posts": {"id", "title"},
+	}
+	bytes, err := GenerateTables(tableColumnMap, tableColumnsSorted, "test", true, false, false)
+
+	Convey("Should be able to generate a struct per table", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, expectedStruct)
+	})
+}
+
 // TestMysqlTypeToGureguType generates the struct and outputs nullable columns as guregu null types
 func TestMysqlTypeToGureguType(t *testing.T) {
 	expectedStruct :=
 		`package test
 
+import "gopkg.in/guregu/null.v4"
+
 type testStruct struct {
 	BigInt    int64
 	Date      null.Time
@@ -393,9 +490,9 @@ type testStruct struct {
 	Double    float64
 	Float     null.Float
 	Int       null.Int
-	Time      time.Time
+	Time      string
 	TimeStamp null.Time
-	TinyInt   int
+	TinyInt   int8
 	VarChar   null.String
 }
 `
@@ -414,10 +511,60 @@ type testStruct struct {
 		"TimeStamp": {"nullable": "YES", "value": "timestamp"},
 	}
 
-	bytes, err := Generate(columnMap, "test_table", "testStruct", "test", false, false, true)
+	bytes, err := Generate(columnMap, sortedColumns(columnMap), "test_table", "testStruct", "test", false, false, true)
 
 	Convey("Should be able to generate map for guregu types", t, func() {
 		So(err, ShouldBeNil)
 		So(string(bytes), ShouldEqual, expectedStruct)
 	})
 }
+
+func TestMysqlTypeCoverage(t *testing.T) {
+	Convey("Should convert every mysql and mariadb data type", t, func() {
+		So(mysqlTypeToGoType("year", "year", true, false), ShouldEqual, sqlNullInt)
+		So(mysqlTypeToGoType("datetime", "datetime(6)", true, false), ShouldEqual, sqlNullTime)
+		So(mysqlTypeToGoType("bigint", "bigint(20) unsigned", true, false), ShouldEqual, "*uint64")
+		So(mysqlTypeToGoType("bigint", "bigint unsigned", true, true), ShouldEqual, "*uint64")
+		So(mysqlTypeToGoType("bigint", "bigint", true, false), ShouldEqual, sqlNullInt)
+		So(mysqlTypeToGoType("time", "time", false, false), ShouldEqual, "string")
+		So(mysqlTypeToGoType("time", "time", true, true), ShouldEqual, gureguNullString)
+		So(mysqlTypeToGoType("set", "set", false, false), ShouldEqual, "string")
+		So(mysqlTypeToGoType("bit", "bit", true, true), ShouldEqual, golangByteArray)
+		So(mysqlTypeToGoType("tinyblob", "tinyblob", false, false), ShouldEqual, golangByteArray)
+		So(mysqlTypeToGoType("bool", "bool", true, true), ShouldEqual, gureguNullBool)
+		So(mysqlTypeToGoType("BOOLEAN", "BOOLEAN", false, false), ShouldEqual, golangBool)
+		So(mysqlTypeToGoType("point", "point", false, false), ShouldEqual, golangByteArray)
+		So(mysqlTypeToGoType("geomcollection", "geomcollection", true, false), ShouldEqual, golangByteArray)
+		So(mysqlTypeToGoType("numeric", "numeric", true, false), ShouldEqual, sqlNullFloat)
+		So(mysqlTypeToGoType("inet6", "inet6", true, true), ShouldEqual, gureguNullString)
+		So(mysqlTypeToGoType("uuid", "uuid", false, false), ShouldEqual, "string")
+		So(mysqlTypeToGoType("unknown", "unknown", false, false), ShouldEqual, "")
+	})
+}
+
+func TestUnknownTypeGenerate(t *testing.T) {
+	table := &Table{Name: "shapes", Columns: []*Column{{Name: "shape", DataType: "hologram"}}}
+
+	_, err := GenerateStruct(table, "", GenerateOptions{PackageName: "test"})
+	Convey("Should get an error for a column of an unknown data type", t, func() {
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "shape")
+		So(err.Error(), ShouldContainSubstring, "hologram")
+	})
+
+	bytes, err := GenerateStruct(table, "", GenerateOptions{PackageName: "test", FallbackType: "interface{}"})
+	Convey("Should use the fallback type for a column of an unknown data type", t, func() {
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldContainSubstring, "Shape interface{}\n")
+	})
+}
+
+// sortedColumns returns the column names of a column map in a stable order
+func sortedColumns(columnMap map[string]map[string]string) []string {
+	columns := make([]string, 0, len(columnMap))
+	for column := range columnMap {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}